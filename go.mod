@@ -0,0 +1,3 @@
+module github.com/LinoTelschow/golib
+
+go 1.21
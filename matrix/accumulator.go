@@ -0,0 +1,177 @@
+/*	This file implements the streaming statistics accumulator
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// NewAccumulator creates an accumulator, whose quantile sketch uses about
+// compression centroids. Larger values give more accurate quantiles,
+// compression = 0 selects the default 100.
+// Returns an error wrapping ErrInvalidArgument, if compression < 0.
+func NewAccumulator(compression float64) (acc *Accumulator, e error) {
+	if compression < 0 || math.IsNaN(compression) {
+		e = fmt.Errorf("%w: compression %v", ErrInvalidArgument, compression)
+		return
+	}
+	acc = new(Accumulator)
+	acc.digest.compression = compression
+	return
+}
+
+// Add adds the sample x.
+// NaN samples make the moments, the extrema and all quantiles NaN,
+// like for Vector.Quantile.
+func (acc *Accumulator) Add(x float64) {
+	acc.mu.Lock()
+	acc.add(x)
+	acc.mu.Unlock()
+}
+
+// AddVec adds all elements of v.
+func (acc *Accumulator) AddVec(v *Vector) {
+	acc.mu.Lock()
+	for i := 0; i < v.Size(); i++ {
+		acc.add(v.at(i))
+	}
+	acc.mu.Unlock()
+}
+
+// Merge adds all samples of o, e.g. of an accumulator filled by another
+// goroutine. o is not modified.
+func (acc *Accumulator) Merge(o *Accumulator) {
+	if acc == o {
+		return
+	}
+	// copy o first, so that no two locks are held at once
+	o.mu.Lock()
+	m := o.m
+	min, max := o.min, o.max
+	var d tdigest
+	d.centroids = append(d.centroids, o.digest.centroids...)
+	d.buffer = append(d.buffer, o.digest.buffer...)
+	o.mu.Unlock()
+
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	if m.n == 0 {
+		return
+	}
+	if acc.m.n == 0 {
+		acc.min, acc.max = min, max
+	} else {
+		acc.min, acc.max = math.Min(acc.min, min), math.Max(acc.max, max)
+	}
+	acc.m.merge(&m)
+	acc.digest.merge(&d)
+}
+
+// Reset removes all samples.
+func (acc *Accumulator) Reset() {
+	acc.mu.Lock()
+	acc.m = moments{}
+	acc.digest = tdigest{compression: acc.digest.compression}
+	acc.mu.Unlock()
+}
+
+// Count returns the number of samples
+func (acc *Accumulator) Count() int {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	return int(acc.m.n)
+}
+
+// Mean returns the mean of the samples.
+// returns NaN without samples
+func (acc *Accumulator) Mean() float64 {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	if acc.m.n == 0 {
+		return math.NaN()
+	}
+	return acc.m.mean
+}
+
+// Var returns the sample variance (divisor n-1) like Vector.Var.
+func (acc *Accumulator) Var() float64 {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	return acc.m.variance()
+}
+
+// Std returns the sample standard deviation sqrt(Var()).
+func (acc *Accumulator) Std() float64 {
+	return math.Sqrt(acc.Var())
+}
+
+// Skewness returns the sample skewness like Vector.Skewness.
+func (acc *Accumulator) Skewness() float64 {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	return acc.m.skewness()
+}
+
+// Kurtosis returns the sample excess kurtosis like Vector.Kurtosis.
+func (acc *Accumulator) Kurtosis() float64 {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	return acc.m.kurtosis()
+}
+
+// Min returns the smallest sample.
+// returns NaN without samples
+func (acc *Accumulator) Min() float64 {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	if acc.m.n == 0 {
+		return math.NaN()
+	}
+	return acc.min
+}
+
+// Max returns the largest sample.
+// returns NaN without samples
+func (acc *Accumulator) Max() float64 {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	if acc.m.n == 0 {
+		return math.NaN()
+	}
+	return acc.max
+}
+
+// Quantile returns the approximate p-quantile of the samples, estimated
+// with a t-digest. The error is smallest for p near 0 and 1.
+// returns NaN without samples, if a sample is NaN or p is not in [0,1]
+func (acc *Accumulator) Quantile(p float64) float64 {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	// the extrema are NaN, once a NaN sample was added
+	if acc.m.n > 0 && math.IsNaN(acc.min) {
+		return math.NaN()
+	}
+	return acc.digest.quantile(p, acc.min, acc.max)
+}
+
+// Median returns the approximate median of the samples.
+func (acc *Accumulator) Median() float64 {
+	return acc.Quantile(0.5)
+}
+
+// add adds x, the caller holds the lock
+func (acc *Accumulator) add(x float64) {
+	if acc.m.n == 0 {
+		acc.min, acc.max = x, x
+	} else {
+		acc.min, acc.max = math.Min(acc.min, x), math.Max(acc.max, x)
+	}
+	acc.m.add(x)
+	// the digest only holds numbers, NaN is tracked by the extrema
+	if !math.IsNaN(x) {
+		acc.digest.add(x, 1)
+	}
+}
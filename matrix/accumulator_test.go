@@ -0,0 +1,143 @@
+package matrix
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestAccumulatorMerge(t *testing.T) {
+	rng := rand.New(rand.NewSource(23))
+	n := 100000
+	v := ZeroVec(n)
+	for i := 0; i < n; i++ {
+		v.Set(i, rng.NormFloat64()*3+1e6)
+	}
+	// fill four accumulators concurrently and merge them
+	parts := make([]*Accumulator, 4)
+	var wg sync.WaitGroup
+	for p := range parts {
+		parts[p], _ = NewAccumulator(0)
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := p; i < n; i += len(parts) {
+				parts[p].Add(v.Get(i))
+			}
+		}(p)
+	}
+	wg.Wait()
+	var acc Accumulator
+	for _, p := range parts {
+		acc.Merge(p)
+	}
+	tests := []struct {
+		name      string
+		got, want float64
+		tol       float64
+	}{
+		{"Count", float64(acc.Count()), float64(n), 0},
+		{"Mean", acc.Mean(), v.Mean(), 1e-8},
+		{"Var", acc.Var(), v.Var(), 1e-8},
+		{"Skewness", acc.Skewness(), v.Skewness(), 1e-6},
+		{"Kurtosis", acc.Kurtosis(), v.Kurtosis(), 1e-6},
+		{"Min", acc.Min(), v.MinValue(), 0},
+		{"Max", acc.Max(), v.MaxValue(), 0},
+		{"Quantile 0", acc.Quantile(0), v.MinValue(), 0},
+		{"Quantile 1", acc.Quantile(1), v.MaxValue(), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if math.Abs(tt.got-tt.want) > tt.tol {
+				t.Fatal(tt.got, tt.want)
+			}
+		})
+	}
+	// compare the quantiles by their rank in the samples
+	for _, p := range []float64{0.001, 0.01, 0.1, 0.5, 0.9, 0.99, 0.999} {
+		q := acc.Quantile(p)
+		rank := 0
+		for _, x := range v.Slice() {
+			if x <= q {
+				rank++
+			}
+		}
+		if d := math.Abs(float64(rank)/float64(n) - p); d > 0.01*math.Max(math.Sqrt(p*(1-p)), 0.05) {
+			t.Fatal("Quantile", p, d)
+		}
+	}
+}
+
+func TestAccumulatorSmall(t *testing.T) {
+	var single Accumulator
+	single.AddVec(VecFromSlice([]float64{5}))
+	small, _ := NewAccumulator(20)
+	small.AddVec(VecFromSlice([]float64{1, 2, 3, 4, 5}))
+	var empty Accumulator
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"single Median", single.Median(), 5},
+		{"single Var", single.Var(), 0},
+		{"small Median", small.Median(), 3},
+		{"empty Mean", empty.Mean(), math.NaN()},
+		{"empty Min", empty.Min(), math.NaN()},
+		{"empty Quantile", empty.Quantile(0.5), math.NaN()},
+		{"invalid p", small.Quantile(1.5), math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !floatClose(tt.got, tt.want, 1e-12) {
+				t.Fatal(tt.got)
+			}
+		})
+	}
+	small.Reset()
+	if small.Count() != 0 || !math.IsNaN(small.Median()) {
+		t.Fatal("Reset")
+	}
+	if _, e := NewAccumulator(-1); e == nil {
+		t.Fatal("expected error for negative compression")
+	}
+}
+
+func TestAccumulatorNaN(t *testing.T) {
+	var acc Accumulator
+	acc.AddVec(VecFromSlice([]float64{1, 2, math.NaN(), 4, 5}))
+	for _, p := range []float64{0, 0.01, 0.5, 0.99, 1} {
+		if q := acc.Quantile(p); !math.IsNaN(q) {
+			t.Fatal(p, q)
+		}
+	}
+	if !math.IsNaN(acc.Min()) || !math.IsNaN(acc.Max()) || !math.IsNaN(acc.Mean()) {
+		t.Fatal("moments and extrema")
+	}
+	// a NaN is kept by merging
+	var b Accumulator
+	b.AddVec(VecFromSlice([]float64{1, 2, 3}))
+	b.Merge(&acc)
+	if !math.IsNaN(b.Median()) {
+		t.Fatal("Merge")
+	}
+	acc.Reset()
+	acc.Add(3)
+	if acc.Median() != 3 || acc.Quantile(1) != 3 {
+		t.Fatal("Reset")
+	}
+}
+
+func TestAccumulatorConcurrentMerge(t *testing.T) {
+	a, b := &Accumulator{}, &Accumulator{}
+	a.Add(1)
+	b.Add(2)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); a.Merge(b) }()
+	go func() { defer wg.Done(); b.Merge(a) }()
+	wg.Wait()
+	if a.Count() < 2 || b.Count() < 2 {
+		t.Fatal(a.Count(), b.Count())
+	}
+}
@@ -0,0 +1,232 @@
+/*	This file implements row-wise and column-wise reductions of matrices
+	and the broadcasting of a vector across the rows or columns of a matrix
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+// RowSums returns the vector of the sums of the rows of a,
+// computed with compensated summation.
+func (a *Matrix) RowSums() (v *Vector) {
+	v = a.reduceRows((*Vector).Sum)
+	return
+}
+
+// ColSums returns the vector of the sums of the columns of a,
+// computed with compensated summation.
+func (a *Matrix) ColSums() (v *Vector) {
+	v = a.reduceCols((*Vector).Sum)
+	return
+}
+
+// RowMeans returns the vector of the means of the rows of a.
+func (a *Matrix) RowMeans() (v *Vector) {
+	v = a.reduceRows((*Vector).Mean)
+	return
+}
+
+// ColMeans returns the vector of the means of the columns of a.
+func (a *Matrix) ColMeans() (v *Vector) {
+	v = a.reduceCols((*Vector).Mean)
+	return
+}
+
+// RowMins returns the vector of the minima of the rows of a.
+func (a *Matrix) RowMins() (v *Vector) {
+	v = a.reduceRows((*Vector).MinValue)
+	return
+}
+
+// ColMins returns the vector of the minima of the columns of a.
+func (a *Matrix) ColMins() (v *Vector) {
+	v = a.reduceCols((*Vector).MinValue)
+	return
+}
+
+// RowMaxes returns the vector of the maxima of the rows of a.
+func (a *Matrix) RowMaxes() (v *Vector) {
+	v = a.reduceRows((*Vector).MaxValue)
+	return
+}
+
+// ColMaxes returns the vector of the maxima of the columns of a.
+func (a *Matrix) ColMaxes() (v *Vector) {
+	v = a.reduceCols((*Vector).MaxValue)
+	return
+}
+
+// RowMaxIdx returns the column indices of the maxima of the rows of a.
+// Ties are resolved by the smallest index.
+func (a *Matrix) RowMaxIdx() (idx []int) {
+	idx = make([]int, a.rows)
+	for i := range idx {
+		idx[i] = a.RowView(i).MaxIdx()
+	}
+	return
+}
+
+// ColMaxIdx returns the row indices of the maxima of the columns of a.
+// Ties are resolved by the smallest index.
+func (a *Matrix) ColMaxIdx() (idx []int) {
+	idx = make([]int, a.cols)
+	for j := range idx {
+		idx[j] = a.ColView(j).MaxIdx()
+	}
+	return
+}
+
+// RowNorms returns the vector of the p-norms of the rows of a.
+// The norms are NaN for p < 1 like in Vector.Norm.
+func (a *Matrix) RowNorms(p float64) (v *Vector) {
+	v = a.reduceRows(func(r *Vector) float64 { return r.Norm(p) })
+	return
+}
+
+// ColNorms returns the vector of the p-norms of the columns of a.
+// The norms are NaN for p < 1 like in Vector.Norm.
+func (a *Matrix) ColNorms(p float64) (v *Vector) {
+	v = a.reduceCols(func(c *Vector) float64 { return c.Norm(p) })
+	return
+}
+
+// AddRowVec returns c with c_ij = a_ij + v_j, i.e. v added to every row of a.
+// Returns nil, if v.Size() != a.Cols().
+func (a *Matrix) AddRowVec(v *Vector) (c *Matrix) {
+	c, _ = a.AddRowVecSafe(v)
+	return
+}
+
+// AddRowVecSafe returns a with v added to every row, like AddRowVec.
+// Returns a DimensionError, if v.Size() != a.Cols().
+func (a *Matrix) AddRowVecSafe(v *Vector) (c *Matrix, e error) {
+	c, e = a.broadcastRow("AddRowVec", v, kernel{op: opAdd})
+	return
+}
+
+// SubRowVec returns c with c_ij = a_ij - v_j, i.e. v subtracted from every row of a.
+// Returns nil, if v.Size() != a.Cols().
+func (a *Matrix) SubRowVec(v *Vector) (c *Matrix) {
+	c, _ = a.SubRowVecSafe(v)
+	return
+}
+
+// SubRowVecSafe returns a with v subtracted from every row, like SubRowVec.
+// Returns a DimensionError, if v.Size() != a.Cols().
+func (a *Matrix) SubRowVecSafe(v *Vector) (c *Matrix, e error) {
+	c, e = a.broadcastRow("SubRowVec", v, kernel{op: opSub})
+	return
+}
+
+// CWiseProdRowVec returns c with c_ij = a_ij * v_j, i.e. every row of a
+// multiplied component-wise by v (a * diag(v)).
+// Returns nil, if v.Size() != a.Cols().
+func (a *Matrix) CWiseProdRowVec(v *Vector) (c *Matrix) {
+	c, _ = a.CWiseProdRowVecSafe(v)
+	return
+}
+
+// CWiseProdRowVecSafe returns every row of a multiplied component-wise by v,
+// like CWiseProdRowVec.
+// Returns a DimensionError, if v.Size() != a.Cols().
+func (a *Matrix) CWiseProdRowVecSafe(v *Vector) (c *Matrix, e error) {
+	c, e = a.broadcastRow("CWiseProdRowVec", v, kernel{op: opCWiseProd})
+	return
+}
+
+// AddColVec returns c with c_ij = a_ij + v_i, i.e. v added to every column of a.
+// Returns nil, if v.Size() != a.Rows().
+func (a *Matrix) AddColVec(v *Vector) (c *Matrix) {
+	c, _ = a.AddColVecSafe(v)
+	return
+}
+
+// AddColVecSafe returns a with v added to every column, like AddColVec.
+// Returns a DimensionError, if v.Size() != a.Rows().
+func (a *Matrix) AddColVecSafe(v *Vector) (c *Matrix, e error) {
+	c, e = a.broadcastCol("AddColVec", v, kernel{op: opAdd})
+	return
+}
+
+// SubColVec returns c with c_ij = a_ij - v_i, i.e. v subtracted from every column of a.
+// Returns nil, if v.Size() != a.Rows().
+func (a *Matrix) SubColVec(v *Vector) (c *Matrix) {
+	c, _ = a.SubColVecSafe(v)
+	return
+}
+
+// SubColVecSafe returns a with v subtracted from every column, like SubColVec.
+// Returns a DimensionError, if v.Size() != a.Rows().
+func (a *Matrix) SubColVecSafe(v *Vector) (c *Matrix, e error) {
+	c, e = a.broadcastCol("SubColVec", v, kernel{op: opSub})
+	return
+}
+
+// CWiseProdColVec returns c with c_ij = a_ij * v_i, i.e. every column of a
+// multiplied component-wise by v (diag(v) * a).
+// Returns nil, if v.Size() != a.Rows().
+func (a *Matrix) CWiseProdColVec(v *Vector) (c *Matrix) {
+	c, _ = a.CWiseProdColVecSafe(v)
+	return
+}
+
+// CWiseProdColVecSafe returns every column of a multiplied component-wise
+// by v, like CWiseProdColVec.
+// Returns a DimensionError, if v.Size() != a.Rows().
+func (a *Matrix) CWiseProdColVecSafe(v *Vector) (c *Matrix, e error) {
+	c, e = a.broadcastCol("CWiseProdColVec", v, kernel{op: opCWiseProd})
+	return
+}
+
+// reduceRows returns the vector of f applied on the rows of a
+func (a *Matrix) reduceRows(f func(*Vector) float64) (v *Vector) {
+	v = ZeroVec(a.rows)
+	for i := range v.entries {
+		v.entries[i] = f(a.RowView(i))
+	}
+	return
+}
+
+// reduceCols returns the vector of f applied on the columns of a
+func (a *Matrix) reduceCols(f func(*Vector) float64) (v *Vector) {
+	v = ZeroVec(a.cols)
+	for j := range v.entries {
+		v.entries[j] = f(a.ColView(j))
+	}
+	return
+}
+
+// broadcastRow applies the kernel on every row of a and the row vector v
+func (a *Matrix) broadcastRow(op string, v *Vector, k kernel) (c *Matrix, e error) {
+	// check sizes
+	if v.Size() != a.cols {
+		e = &DimensionError{Op: op, A: a.shape(), B: v.shape()}
+		return
+	}
+	c, _ = ZeroMat(a.rows, a.cols)
+	y := v.Slice()
+	var buf []float64
+	for i := 0; i < a.rows; i++ {
+		k.apply(c.row(i), a.rowInto(i, &buf), y)
+	}
+	return
+}
+
+// broadcastCol applies the kernel on every column of a and the column vector v
+func (a *Matrix) broadcastCol(op string, v *Vector, k kernel) (c *Matrix, e error) {
+	// check sizes
+	if v.Size() != a.rows {
+		e = &DimensionError{Op: op, A: a.shape(), B: v.shape()}
+		return
+	}
+	c, _ = ZeroMat(a.rows, a.cols)
+	// the i-th row is combined with the constant v_i
+	y := make([]float64, a.cols)
+	var buf []float64
+	for i := 0; i < a.rows; i++ {
+		for j := range y {
+			y[j] = v.at(i)
+		}
+		k.apply(c.row(i), a.rowInto(i, &buf), y)
+	}
+	return
+}
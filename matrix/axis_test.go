@@ -0,0 +1,82 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestReductions(t *testing.T) {
+	a, _ := MatrixFromSlice([][]float64{{1, -5, 3}, {4, 2, 4}})
+	at := a.T()
+	tests := []struct {
+		name      string
+		got, want *Vector
+	}{
+		{"RowSums", a.RowSums(), VecFromSlice([]float64{-1, 10})},
+		{"ColSums", a.ColSums(), VecFromSlice([]float64{5, -3, 7})},
+		{"RowMeans", a.RowMeans(), VecFromSlice([]float64{-1.0 / 3, 10.0 / 3})},
+		{"ColMeans", a.ColMeans(), VecFromSlice([]float64{2.5, -1.5, 3.5})},
+		{"RowMins", a.RowMins(), VecFromSlice([]float64{-5, 2})},
+		{"ColMins", a.ColMins(), VecFromSlice([]float64{1, -5, 3})},
+		{"RowMaxes", a.RowMaxes(), VecFromSlice([]float64{3, 4})},
+		{"ColMaxes", a.ColMaxes(), VecFromSlice([]float64{4, 2, 4})},
+		{"RowNorms 2", a.RowNorms(2), VecFromSlice([]float64{math.Sqrt(35), 6})},
+		{"ColNorms 1", a.ColNorms(1), VecFromSlice([]float64{5, 7, 7})},
+		{"ColNorms Inf", a.ColNorms(math.Inf(1)), VecFromSlice([]float64{4, 5, 4})},
+		{"RowSums of T", at.RowSums(), a.ColSums()},
+		{"ColSums of T", at.ColSums(), a.RowSums()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vecClose(t, tt.name, tt.got, tt.want, 1e-14)
+		})
+	}
+	ri, ci := a.RowMaxIdx(), a.ColMaxIdx()
+	if ri[0] != 2 || ri[1] != 0 || ci[0] != 1 || ci[1] != 1 || ci[2] != 1 {
+		t.Fatal("MaxIdx", ri, ci)
+	}
+	rng := rand.New(rand.NewSource(25))
+	m := randMat(50, 30, rng)
+	var s float64
+	for i := 0; i < 50; i++ {
+		s += m.Get(i, 7)
+	}
+	if math.Abs(m.ColSums().Get(7)-s) > 1e-12 {
+		t.Fatal("ColSums", m.ColSums().Get(7), s)
+	}
+}
+
+func TestBroadcasting(t *testing.T) {
+	a, _ := MatrixFromSlice([][]float64{{1, -5, 3}, {4, 2, 4}})
+	r := VecFromSlice([]float64{10, 20, 30})
+	c := VecFromSlice([]float64{1, 2})
+	tests := []struct {
+		name string
+		got  *Matrix
+		want [][]float64
+	}{
+		{"AddRowVec", a.AddRowVec(r), [][]float64{{11, 15, 33}, {14, 22, 34}}},
+		{"SubRowVec", a.SubRowVec(r), [][]float64{{-9, -25, -27}, {-6, -18, -26}}},
+		{"CWiseProdRowVec", a.CWiseProdRowVec(r), [][]float64{{10, -100, 90}, {40, 40, 120}}},
+		{"AddColVec", a.AddColVec(c), [][]float64{{2, -4, 4}, {6, 4, 6}}},
+		{"SubColVec", a.SubColVec(c), [][]float64{{0, -6, 2}, {2, 0, 2}}},
+		{"CWiseProdColVec", a.CWiseProdColVec(c), [][]float64{{1, -5, 3}, {8, 4, 8}}},
+		{"AddColVec of T", a.T().AddColVec(r), [][]float64{{11, 14}, {15, 22}, {33, 34}}},
+		{"AddRowVec of view", a.AddRowVec(VecFromSlice([]float64{9, 10, 20, 30, 7}).SubVecView(1, 3)), [][]float64{{11, 15, 33}, {14, 22, 34}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, _ := MatrixFromSlice(tt.want)
+			matClose(t, tt.name, tt.got, want, 0)
+		})
+	}
+	if a.AddRowVec(c) != nil || a.AddColVec(r) != nil {
+		t.Fatal("mismatched broadcasts should be nil")
+	}
+	var de *DimensionError
+	if _, e := a.SubColVecSafe(r); !errors.As(e, &de) || !errors.Is(e, ErrDimensionMismatch) {
+		t.Fatal(e)
+	}
+}
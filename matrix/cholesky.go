@@ -0,0 +1,222 @@
+/*	This file implements the Cholesky decomposition of
+	symmetric positive definite matrices
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"math"
+)
+
+// NewCholesky computes the Cholesky decomposition a = L * L^T of the
+// symmetric positive definite matrix a. Only the lower triangle of a is read.
+// Returns ErrNotPositiveDefinite, if a is not positive definite.
+func NewCholesky(a Mat) (f *Cholesky, e error) {
+	f, e = newCholesky(asMatrix(a))
+	return
+}
+
+// newCholesky implements NewCholesky for a dense matrix
+func newCholesky(a *Matrix) (f *Cholesky, e error) {
+	// check dimensions
+	if a.rows != a.cols {
+		e = ErrNotSquare
+		return
+	}
+	n := a.rows
+	l, _ := ZeroMat(n, n)
+	for j := 0; j < n; j++ {
+		// diagonal entry
+		d := a.getEntry(j, j)
+		for k := 0; k < j; k++ {
+			d -= l.entries[j*n+k] * l.entries[j*n+k]
+		}
+		if d <= 0 || math.IsNaN(d) {
+			e = ErrNotPositiveDefinite
+			return
+		}
+		ljj := math.Sqrt(d)
+		l.entries[j*n+j] = ljj
+		// entries below the diagonal
+		for i := j + 1; i < n; i++ {
+			s := a.getEntry(i, j)
+			for k := 0; k < j; k++ {
+				s -= l.entries[i*n+k] * l.entries[j*n+k]
+			}
+			l.entries[i*n+j] = s / ljj
+		}
+	}
+	f = new(Cholesky)
+	f.l = l
+	return
+}
+
+// L returns the lower triangular factor.
+func (f *Cholesky) L() (l *Matrix) {
+	l = f.l.CopyMat()
+	return
+}
+
+// Size returns the number of rows (and columns) of the factorized matrix.
+func (f *Cholesky) Size() int {
+	return f.l.rows
+}
+
+// Det returns the determinant of the factorized matrix.
+func (f *Cholesky) Det() float64 {
+	return math.Exp(f.LogDet())
+}
+
+// LogDet returns the natural logarithm of the determinant
+// of the factorized matrix.
+func (f *Cholesky) LogDet() float64 {
+	n := f.l.rows
+	var result float64 = 0
+	for i := 0; i < n; i++ {
+		result += math.Log(f.l.entries[i*n+i])
+	}
+	return 2 * result
+}
+
+// Solve returns the solution x of A * x = b.
+// Returns an error, if the sizes don't match.
+func (f *Cholesky) Solve(b *Vector) (x *Vector, e error) {
+	// check sizes
+	if b.Size() != f.l.rows {
+		e = &DimensionError{Op: "Solve", A: f.l.shape(), B: b.shape()}
+		return
+	}
+	x = b.CopyVec()
+	f.solveInPlace(x.entries)
+	return
+}
+
+// SolveMat returns the solution X of A * X = B.
+// Returns an error, if the sizes don't match.
+func (f *Cholesky) SolveMat(b *Matrix) (x *Matrix, e error) {
+	n := f.l.rows
+	// check sizes
+	if b.rows != n {
+		e = &DimensionError{Op: "SolveMat", A: f.l.shape(), B: b.shape()}
+		return
+	}
+	x, _ = ZeroMat(n, b.cols)
+	col := make([]float64, n)
+	for j := 0; j < b.cols; j++ {
+		for i := 0; i < n; i++ {
+			col[i] = b.getEntry(i, j)
+		}
+		f.solveInPlace(col)
+		for i := 0; i < n; i++ {
+			x.entries[i*b.cols+j] = col[i]
+		}
+	}
+	return
+}
+
+// Update modifies the factorization, such that it factorizes A + v * v^T.
+// Returns an error, if the sizes don't match.
+func (f *Cholesky) Update(v *Vector) (e error) {
+	// check sizes
+	if v.Size() != f.l.rows {
+		e = &DimensionError{Op: "Update", A: f.l.shape(), B: v.shape()}
+		return
+	}
+	n := f.l.rows
+	l := f.l.entries
+	x := v.Slice()
+	for k := 0; k < n; k++ {
+		lkk := l[k*n+k]
+		r := math.Hypot(lkk, x[k])
+		c := r / lkk
+		s := x[k] / lkk
+		l[k*n+k] = r
+		for i := k + 1; i < n; i++ {
+			l[i*n+k] = (l[i*n+k] + s*x[i]) / c
+			x[i] = c*x[i] - s*l[i*n+k]
+		}
+	}
+	return
+}
+
+// Downdate modifies the factorization, such that it factorizes A - v * v^T.
+// Returns ErrNotPositiveDefinite and leaves the factorization unchanged,
+// if A - v * v^T is not positive definite.
+func (f *Cholesky) Downdate(v *Vector) (e error) {
+	// check sizes
+	if v.Size() != f.l.rows {
+		e = &DimensionError{Op: "Downdate", A: f.l.shape(), B: v.shape()}
+		return
+	}
+	n := f.l.rows
+	// work on a copy, such that a failure doesn't destroy the factorization
+	lm := f.l.CopyMat()
+	l := lm.entries
+	x := v.Slice()
+	for k := 0; k < n; k++ {
+		lkk := l[k*n+k]
+		d := (lkk - x[k]) * (lkk + x[k])
+		if d <= 0 || math.IsNaN(d) {
+			e = ErrNotPositiveDefinite
+			return
+		}
+		r := math.Sqrt(d)
+		c := r / lkk
+		s := x[k] / lkk
+		l[k*n+k] = r
+		for i := k + 1; i < n; i++ {
+			l[i*n+k] = (l[i*n+k] - s*x[i]) / c
+			x[i] = c*x[i] - s*l[i*n+k]
+		}
+	}
+	f.l = lm
+	return
+}
+
+// solveInPlace overwrites x with the solution of L * L^T * x = x.
+func (f *Cholesky) solveInPlace(x []float64) {
+	n := f.l.rows
+	l := f.l.entries
+	// forward substitution with L
+	for i := 0; i < n; i++ {
+		sum := x[i]
+		for k := 0; k < i; k++ {
+			sum -= l[i*n+k] * x[k]
+		}
+		x[i] = sum / l[i*n+i]
+	}
+	// backward substitution with L^T
+	for i := n - 1; i >= 0; i-- {
+		sum := x[i]
+		for k := i + 1; k < n; k++ {
+			sum -= l[k*n+i] * x[k]
+		}
+		x[i] = sum / l[i*n+i]
+	}
+}
+
+// Mahalanobis returns the Mahalanobis distance sqrt((a-b)^T * A^-1 * (a-b))
+// of a and b with respect to the factorized covariance matrix A.
+// Use it instead of Vector.MahalanobisDist for many distances.
+// Returns a DimensionError, if the sizes don't match.
+func (f *Cholesky) Mahalanobis(a, b *Vector) (d float64, e error) {
+	n := f.l.rows
+	// check sizes
+	if a.Size() != n || b.Size() != n {
+		e = &DimensionError{Op: "Mahalanobis", A: a.shape(), B: b.shape()}
+		return
+	}
+	// ||L^-1 * (a-b)|| by forward substitution
+	y := a.Sub(b)
+	l := f.l.entries
+	for i := 0; i < n; i++ {
+		sum := y.entries[i]
+		for k := 0; k < i; k++ {
+			sum -= l[i*n+k] * y.entries[k]
+		}
+		y.entries[i] = sum / l[i*n+i]
+	}
+	d = y.Norm2()
+	return
+}
@@ -0,0 +1,77 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// spdMat returns a random symmetric positive definite n x n matrix
+func spdMat(n int, rng *rand.Rand) *Matrix {
+	g := randMat(n+3, n, rng)
+	return g.TMul(g)
+}
+
+func TestCholesky(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	for _, n := range []int{1, 2, 5, 15} {
+		a := spdMat(n, rng)
+		f, e := NewCholesky(a)
+		if e != nil {
+			t.Fatal(n, e)
+		}
+		l := f.L()
+		matClose(t, "L L^T", l.MulT(l), a, 1e-10)
+		b := randVec(n, rng)
+		x, e := f.Solve(b)
+		if e != nil {
+			t.Fatal(n, e)
+		}
+		vecClose(t, "Solve", a.MulVec(x), b, 1e-9)
+		if math.Abs(f.LogDet()-math.Log(a.Det())) > 1e-9 {
+			t.Fatal(f.LogDet(), math.Log(a.Det()))
+		}
+	}
+}
+
+func TestCholeskyUpdate(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	a := spdMat(5, rng)
+	f, _ := NewCholesky(a)
+	v := randVec(5, rng)
+	vm := v.Mat()
+	if e := f.Update(v); e != nil {
+		t.Fatal(e)
+	}
+	matClose(t, "Update", f.L().MulT(f.L()), a.Add(vm.MulT(vm)), 1e-10)
+	if e := f.Downdate(v); e != nil {
+		t.Fatal(e)
+	}
+	matClose(t, "Downdate", f.L().MulT(f.L()), a, 1e-10)
+	// a failing downdate leaves the factor unchanged
+	before := f.L()
+	if e := f.Downdate(v.Scale(100)); !errors.Is(e, ErrNotPositiveDefinite) {
+		t.Fatal(e)
+	}
+	matClose(t, "unchanged", f.L(), before, 0)
+}
+
+func TestCholeskyNotPositiveDefinite(t *testing.T) {
+	tests := []struct {
+		name string
+		a    [][]float64
+	}{
+		{"indefinite", [][]float64{{1, 2}, {2, 1}}},
+		{"negative", [][]float64{{-1}}},
+		{"singular", [][]float64{{1, 1}, {1, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := MatrixFromSlice(tt.a)
+			if _, e := NewCholesky(a); !errors.Is(e, ErrNotPositiveDefinite) {
+				t.Fatal(e)
+			}
+		})
+	}
+}
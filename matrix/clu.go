@@ -0,0 +1,130 @@
+/*	This file implements the LU decomposition of complex matrices,
+	the factorization is shared with LU in lu.go
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"math/cmplx"
+)
+
+// NewCLU computes the LU decomposition of the square complex matrix a,
+// such that P * a = L * U. The matrix a is not modified.
+// Singular matrices are factorized as well, but Solve and Inverse
+// return ErrSingular for them.
+func NewCLU(a *CMatrix) (f *CLU, e error) {
+	// check dimensions
+	if a.rows != a.cols {
+		e = ErrNotSquare
+		return
+	}
+	n := a.rows
+	// create factorization, shared with LU
+	f = new(CLU)
+	f.lu = a.CopyMat()
+	f.pivot, f.sign, f.tol = luFactor(f.lu.entries, n, cmplx.Abs)
+	return
+}
+
+// IsSingular reports whether the factorized matrix is singular.
+func (f *CLU) IsSingular() bool {
+	return luSingular(f.lu.entries, f.lu.rows, f.tol, cmplx.Abs)
+}
+
+// Det returns the determinant of the factorized matrix.
+func (f *CLU) Det() complex128 {
+	return luDet(f.lu.entries, f.lu.rows, f.sign)
+}
+
+// Solve returns the solution x of A * x = b.
+// Returns an error, if the sizes don't match or A is singular.
+func (f *CLU) Solve(b *CVector) (x *CVector, e error) {
+	n := f.lu.rows
+	// check sizes
+	if b.Size() != n {
+		e = &DimensionError{Op: "Solve", A: f.lu.shape(), B: b.shape()}
+		return
+	}
+	if f.IsSingular() {
+		e = ErrSingular
+		return
+	}
+	// apply permutation
+	x = ZeroCVec(n)
+	for i := 0; i < n; i++ {
+		x.entries[i] = b.entries[f.pivot[i]]
+	}
+	luSolveInPlace(f.lu.entries, n, x.entries)
+	return
+}
+
+// SolveMat returns the solution X of A * X = B,
+// i.e. it solves for every column of B.
+// Returns an error, if the sizes don't match or A is singular.
+func (f *CLU) SolveMat(b *CMatrix) (x *CMatrix, e error) {
+	n := f.lu.rows
+	// check sizes
+	if b.rows != n {
+		e = &DimensionError{Op: "SolveMat", A: f.lu.shape(), B: b.shape()}
+		return
+	}
+	if f.IsSingular() {
+		e = ErrSingular
+		return
+	}
+	x, _ = ZeroCMat(n, b.cols)
+	col := make([]complex128, n)
+	for j := 0; j < b.cols; j++ {
+		// solve for permuted j-th column
+		for i := 0; i < n; i++ {
+			col[i] = b.entries[f.pivot[i]*b.cols+j]
+		}
+		luSolveInPlace(f.lu.entries, n, col)
+		for i := 0; i < n; i++ {
+			x.entries[i*b.cols+j] = col[i]
+		}
+	}
+	return
+}
+
+// Inverse returns the inverse of the factorized matrix.
+// Returns ErrSingular, if the matrix is singular.
+func (f *CLU) Inverse() (inv *CMatrix, e error) {
+	n := f.lu.rows
+	id, _ := IdCMat(n, n)
+	inv, e = f.SolveMat(id)
+	return
+}
+
+// Det returns the determinant of the square matrix a.
+// Returns NaN if a is not square.
+func (a *CMatrix) Det() complex128 {
+	f, e := NewCLU(a)
+	if e != nil {
+		return cmplx.NaN()
+	}
+	return f.Det()
+}
+
+// Inverse returns the inverse of the square matrix a.
+// Returns an error, if a is not square or singular.
+func (a *CMatrix) Inverse() (inv *CMatrix, e error) {
+	f, e := NewCLU(a)
+	if e != nil {
+		return
+	}
+	inv, e = f.Inverse()
+	return
+}
+
+// Solve returns the solution x of a * x = b for a square matrix a.
+// Use NewCLU to solve for many right hand sides.
+func (a *CMatrix) Solve(b *CVector) (x *CVector, e error) {
+	f, e := NewCLU(a)
+	if e != nil {
+		return
+	}
+	x, e = f.Solve(b)
+	return
+}
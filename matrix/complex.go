@@ -0,0 +1,568 @@
+/*	This file implements the complex matrix and vector types CMatrix and
+	CVector, including conjugate transposes and Hermitian products.
+	They are defined as GenMatrix[complex128] and GenVector[complex128],
+	whose storage, indexing and arithmetic they reuse
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"math/cmplx"
+)
+
+// ZeroCMat creates a complex zero matrix with r rows and c columns
+func ZeroCMat(r, c int) (m *CMatrix, e error) {
+	g, e := ZeroGenMat[complex128](r, c)
+	m = (*CMatrix)(g)
+	return
+}
+
+// IdCMat creates a complex identity matrix with r rows and c columns
+func IdCMat(r, c int) (m *CMatrix, e error) {
+	g, e := IdGenMat[complex128](r, c)
+	m = (*CMatrix)(g)
+	return
+}
+
+// CMatrixFromSlice creates a complex matrix from a 2d slice of type: [][]complex128
+// Returns an error, if the slice is empty or the rows have different lengths
+func CMatrixFromSlice(slice [][]complex128) (m *CMatrix, e error) {
+	g, e := GenMatFromSlice(slice)
+	m = (*CMatrix)(g)
+	return
+}
+
+// CMatFromParts creates the complex matrix re + i*im.
+// If im is nil, the imaginary part is zero.
+// Returns a DimensionError, if re and im have different dimensions.
+func CMatFromParts(re, im *Matrix) (m *CMatrix, e error) {
+	// check dimensions
+	if im != nil && (re.rows != im.rows || re.cols != im.cols) {
+		e = &DimensionError{Op: "CMatFromParts", A: re.shape(), B: im.shape()}
+		return
+	}
+	m, _ = ZeroCMat(re.rows, re.cols)
+	for i := 0; i < re.rows; i++ {
+		for j := 0; j < re.cols; j++ {
+			var y float64 = 0
+			if im != nil {
+				y = im.getEntry(i, j)
+			}
+			m.entries[i*re.cols+j] = complex(re.getEntry(i, j), y)
+		}
+	}
+	return
+}
+
+// ZeroCVec creates a complex zero vector of size n.
+// Returns nil, if n < 1 (like ZeroVec)
+func ZeroCVec(n int) (v *CVector) {
+	v = (*CVector)(ZeroGenVec[complex128](n))
+	return
+}
+
+// CVecFromSlice creates a complex vector with a copy of the slice s.
+// Returns nil, if s is empty (like VecFromSlice)
+func CVecFromSlice(s []complex128) (v *CVector) {
+	v = (*CVector)(GenVecFromSlice(s))
+	return
+}
+
+// CVecFromParts creates the complex vector re + i*im.
+// If im is nil, the imaginary part is zero.
+// Returns a DimensionError, if re and im have different sizes.
+func CVecFromParts(re, im *Vector) (v *CVector, e error) {
+	// check sizes
+	if im != nil && re.Size() != im.Size() {
+		e = &DimensionError{Op: "CVecFromParts", A: re.shape(), B: im.shape()}
+		return
+	}
+	v = ZeroCVec(re.Size())
+	if v == nil {
+		return
+	}
+	for i := range v.entries {
+		var y float64 = 0
+		if im != nil {
+			y = im.at(i)
+		}
+		v.entries[i] = complex(re.at(i), y)
+	}
+	return
+}
+
+// Rows returns the number of rows of the matrix
+func (m *CMatrix) Rows() int {
+	return m.rows
+}
+
+// Cols returns the number of cols of the matrix
+func (m *CMatrix) Cols() int {
+	return m.cols
+}
+
+// Dims returns the number of rows and cols of the matrix
+func (m *CMatrix) Dims() (r, c int) {
+	return m.rows, m.cols
+}
+
+// Get(i,j) returns the element in row i and column j.
+// returns NaN if invalid index
+func (m *CMatrix) Get(i, j int) complex128 {
+	return m.gen().Get(i, j)
+}
+
+// GetSafe(i,j) returns the element in row i and column j and an error value.
+// Returns an IndexError, if invalid indices
+func (m *CMatrix) GetSafe(i, j int) (elem complex128, e error) {
+	elem, e = m.gen().GetSafe(i, j)
+	return
+}
+
+// Set(i,j, v) sets the entry of row i and col j to value v.
+// No update, if invalid indices
+func (m *CMatrix) Set(i, j int, v complex128) {
+	m.gen().Set(i, j, v)
+}
+
+// SetSafe sets the entry of row i and col j to value v.
+// Returns an IndexError, if invalid indices
+func (m *CMatrix) SetSafe(i, j int, v complex128) (e error) {
+	e = m.gen().SetSafe(i, j, v)
+	return
+}
+
+// CopyMat returns a copy of the matrix
+func (a *CMatrix) CopyMat() (m *CMatrix) {
+	m = (*CMatrix)(a.gen().CopyMat())
+	return
+}
+
+// Real returns the real part of the matrix
+func (a *CMatrix) Real() (m *Matrix) {
+	m, _ = ZeroMat(a.rows, a.cols)
+	for k, z := range a.entries {
+		m.entries[k] = real(z)
+	}
+	return
+}
+
+// Imag returns the imaginary part of the matrix
+func (a *CMatrix) Imag() (m *Matrix) {
+	m, _ = ZeroMat(a.rows, a.cols)
+	for k, z := range a.entries {
+		m.entries[k] = imag(z)
+	}
+	return
+}
+
+// Conj returns the complex conjugate of the matrix
+func (a *CMatrix) Conj() (m *CMatrix) {
+	m = a.CopyMat()
+	for k, z := range m.entries {
+		m.entries[k] = cmplx.Conj(z)
+	}
+	return
+}
+
+// T returns the transpose of the matrix (without conjugation)
+func (a *CMatrix) T() (m *CMatrix) {
+	m = (*CMatrix)(a.gen().T())
+	return
+}
+
+// H returns the conjugate transpose a^H of the matrix
+func (a *CMatrix) H() (m *CMatrix) {
+	m, _ = ZeroCMat(a.cols, a.rows)
+	for i := 0; i < a.rows; i++ {
+		for j := 0; j < a.cols; j++ {
+			m.entries[j*a.rows+i] = cmplx.Conj(a.entries[i*a.cols+j])
+		}
+	}
+	return
+}
+
+// Add adds two matrices: c = a + b.
+// Dimension mismatch returns nil.
+func (a *CMatrix) Add(b *CMatrix) (c *CMatrix) {
+	c, _ = a.AddSafe(b)
+	return
+}
+
+// AddSafe adds two matrices: c = a + b.
+// Returns a DimensionError, if dimensions mismatch.
+func (a *CMatrix) AddSafe(b *CMatrix) (c *CMatrix, e error) {
+	g, e := a.gen().AddSafe(b.gen())
+	c = (*CMatrix)(g)
+	return
+}
+
+// Sub subtracts two matrices: c = a - b.
+// Dimension mismatch returns nil.
+func (a *CMatrix) Sub(b *CMatrix) (c *CMatrix) {
+	c, _ = a.SubSafe(b)
+	return
+}
+
+// SubSafe subtracts two matrices: c = a - b.
+// Returns a DimensionError, if dimensions mismatch.
+func (a *CMatrix) SubSafe(b *CMatrix) (c *CMatrix, e error) {
+	g, e := a.gen().SubSafe(b.gen())
+	c = (*CMatrix)(g)
+	return
+}
+
+// CWiseProd computes the component-wise product of two matrices.
+// Dimension mismatch returns nil.
+func (a *CMatrix) CWiseProd(b *CMatrix) (c *CMatrix) {
+	c, _ = a.CWiseProdSafe(b)
+	return
+}
+
+// CWiseProdSafe computes the component-wise product of two matrices.
+// Returns a DimensionError, if dimensions mismatch.
+func (a *CMatrix) CWiseProdSafe(b *CMatrix) (c *CMatrix, e error) {
+	g, e := a.gen().CWiseProdSafe(b.gen())
+	c = (*CMatrix)(g)
+	return
+}
+
+// Scale multiplies every entry with factor
+func (a *CMatrix) Scale(factor complex128) (m *CMatrix) {
+	m = (*CMatrix)(a.gen().Scale(factor))
+	return
+}
+
+// ApplyFunc applies the function f to every entry and returns the result
+func (a *CMatrix) ApplyFunc(f func(complex128) complex128) (m *CMatrix) {
+	m = (*CMatrix)(a.gen().ApplyFunc(f))
+	return
+}
+
+// Mul computes the matrix product c = a * b.
+// Dimension mismatch (a.Cols() != b.Rows()) returns nil.
+func (a *CMatrix) Mul(b *CMatrix) (c *CMatrix) {
+	c, _ = a.MulSafe(b)
+	return
+}
+
+// MulSafe computes the matrix product c = a * b.
+// Returns a DimensionError, if a.Cols() != b.Rows().
+func (a *CMatrix) MulSafe(b *CMatrix) (c *CMatrix, e error) {
+	g, e := a.gen().MulSafe(b.gen())
+	c = (*CMatrix)(g)
+	return
+}
+
+// HMul computes the Hermitian product c = a^H * b without forming a^H.
+// Dimension mismatch (a.Rows() != b.Rows()) returns nil.
+func (a *CMatrix) HMul(b *CMatrix) (c *CMatrix) {
+	c, _ = a.HMulSafe(b)
+	return
+}
+
+// HMulSafe computes the Hermitian product c = a^H * b without forming a^H.
+// Returns a DimensionError, if a.Rows() != b.Rows().
+func (a *CMatrix) HMulSafe(b *CMatrix) (c *CMatrix, e error) {
+	// check if dimensions match
+	if a.rows != b.rows {
+		e = &DimensionError{Op: "HMul", A: a.shape(), B: b.shape()}
+		return
+	}
+	c, _ = ZeroCMat(a.cols, b.cols)
+	// i-k-j loop order runs over rows of b and c
+	for i := 0; i < a.cols; i++ {
+		cRow := c.entries[i*b.cols : (i+1)*b.cols]
+		for k := 0; k < a.rows; k++ {
+			aki := cmplx.Conj(a.entries[k*a.cols+i])
+			bRow := b.entries[k*b.cols : (k+1)*b.cols]
+			for j := range cRow {
+				cRow[j] += aki * bRow[j]
+			}
+		}
+	}
+	return
+}
+
+// MulH computes the Hermitian product c = a * b^H without forming b^H.
+// Dimension mismatch (a.Cols() != b.Cols()) returns nil.
+func (a *CMatrix) MulH(b *CMatrix) (c *CMatrix) {
+	c, _ = a.MulHSafe(b)
+	return
+}
+
+// MulHSafe computes the Hermitian product c = a * b^H without forming b^H.
+// Returns a DimensionError, if a.Cols() != b.Cols().
+func (a *CMatrix) MulHSafe(b *CMatrix) (c *CMatrix, e error) {
+	// check if dimensions match
+	if a.cols != b.cols {
+		e = &DimensionError{Op: "MulH", A: a.shape(), B: b.shape()}
+		return
+	}
+	c, _ = ZeroCMat(a.rows, b.rows)
+	// c_ij is the dot product of row i of a and the conjugated row j of b
+	for i := 0; i < a.rows; i++ {
+		aRow := a.entries[i*a.cols : (i+1)*a.cols]
+		for j := 0; j < b.rows; j++ {
+			bRow := b.entries[j*b.cols : (j+1)*b.cols]
+			var sum complex128
+			for k, z := range aRow {
+				sum += z * cmplx.Conj(bRow[k])
+			}
+			c.entries[i*b.rows+j] = sum
+		}
+	}
+	return
+}
+
+// MulVec computes the matrix-vector product w = a * v.
+// Dimension mismatch (a.Cols() != v.Size()) returns nil.
+func (a *CMatrix) MulVec(v *CVector) (w *CVector) {
+	w, _ = a.MulVecSafe(v)
+	return
+}
+
+// MulVecSafe computes the matrix-vector product w = a * v.
+// Returns a DimensionError, if a.Cols() != v.Size().
+func (a *CMatrix) MulVecSafe(v *CVector) (w *CVector, e error) {
+	g, e := a.gen().MulVecSafe(v.gen())
+	w = (*CVector)(g)
+	return
+}
+
+// HMulVec computes the matrix-vector product w = a^H * v without forming a^H.
+// Dimension mismatch (a.Rows() != v.Size()) returns nil.
+func (a *CMatrix) HMulVec(v *CVector) (w *CVector) {
+	w, _ = a.HMulVecSafe(v)
+	return
+}
+
+// HMulVecSafe computes the matrix-vector product w = a^H * v without forming a^H.
+// Returns a DimensionError, if a.Rows() != v.Size().
+func (a *CMatrix) HMulVecSafe(v *CVector) (w *CVector, e error) {
+	// check if dimensions match
+	if a.rows != v.Size() {
+		e = &DimensionError{Op: "HMulVec", A: a.shape(), B: v.shape()}
+		return
+	}
+	w = ZeroCVec(a.cols)
+	for i, vi := range v.entries {
+		for j, z := range a.entries[i*a.cols : (i+1)*a.cols] {
+			w.entries[j] += cmplx.Conj(z) * vi
+		}
+	}
+	return
+}
+
+// IsHermitian reports whether a is square and |a_ij - conj(a_ji)| <= tol
+// for all entries.
+func (a *CMatrix) IsHermitian(tol float64) bool {
+	if a.rows != a.cols {
+		return false
+	}
+	n := a.rows
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			if cmplx.Abs(a.entries[i*n+j]-cmplx.Conj(a.entries[j*n+i])) > tol {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsUnitary reports whether a is square and |(a^H * a - I)_ij| <= tol
+// for all entries.
+func (a *CMatrix) IsUnitary(tol float64) bool {
+	if a.rows != a.cols {
+		return false
+	}
+	p := a.HMul(a)
+	n := a.rows
+	for i := 0; i < n; i++ {
+		p.entries[i*n+i] -= 1
+	}
+	for _, z := range p.entries {
+		if cmplx.Abs(z) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+// gen returns a as generic matrix without copying
+func (a *CMatrix) gen() *GenMatrix[complex128] {
+	return (*GenMatrix[complex128])(a)
+}
+
+// shape returns the dimensions of a for error values
+func (a *CMatrix) shape() []int {
+	return []int{a.rows, a.cols}
+}
+
+// Size returns the number of elements of the vector
+func (a *CVector) Size() int {
+	return a.gen().Size()
+}
+
+// Get(i) returns the i-th element.
+// returns NaN if invalid index
+func (v *CVector) Get(i int) complex128 {
+	return v.gen().Get(i)
+}
+
+// GetSafe(i) returns the i-th element and an error value.
+// Returns an IndexError, if invalid index
+func (v *CVector) GetSafe(i int) (elem complex128, e error) {
+	elem, e = v.gen().GetSafe(i)
+	return
+}
+
+// Set(i, value) sets the i-th element to value.
+// No update, if invalid index
+func (v *CVector) Set(i int, value complex128) {
+	v.gen().Set(i, value)
+}
+
+// SetSafe sets the i-th element to value.
+// Returns an IndexError, if invalid index
+func (v *CVector) SetSafe(i int, value complex128) (e error) {
+	e = v.gen().SetSafe(i, value)
+	return
+}
+
+// CopyVec returns a copy of the vector
+func (a *CVector) CopyVec() (v *CVector) {
+	v = (*CVector)(a.gen().CopyVec())
+	return
+}
+
+// Slice returns a copy of the elements as slice
+func (a *CVector) Slice() (s []complex128) {
+	s = a.gen().Slice()
+	return
+}
+
+// Real returns the real part of the vector
+func (a *CVector) Real() (v *Vector) {
+	v = ZeroVec(len(a.entries))
+	for i, z := range a.entries {
+		v.entries[i] = real(z)
+	}
+	return
+}
+
+// Imag returns the imaginary part of the vector
+func (a *CVector) Imag() (v *Vector) {
+	v = ZeroVec(len(a.entries))
+	for i, z := range a.entries {
+		v.entries[i] = imag(z)
+	}
+	return
+}
+
+// Conj returns the complex conjugate of the vector
+func (a *CVector) Conj() (v *CVector) {
+	v = a.CopyVec()
+	for i, z := range v.entries {
+		v.entries[i] = cmplx.Conj(z)
+	}
+	return
+}
+
+// Add adds two vectors: c = a + b.
+// Dimension mismatch returns nil.
+func (a *CVector) Add(b *CVector) (c *CVector) {
+	c, _ = a.AddSafe(b)
+	return
+}
+
+// AddSafe adds two vectors: c = a + b.
+// Returns a DimensionError, if sizes mismatch.
+func (a *CVector) AddSafe(b *CVector) (c *CVector, e error) {
+	g, e := a.gen().AddSafe(b.gen())
+	c = (*CVector)(g)
+	return
+}
+
+// Sub subtracts two vectors: c = a - b.
+// Dimension mismatch returns nil.
+func (a *CVector) Sub(b *CVector) (c *CVector) {
+	c, _ = a.SubSafe(b)
+	return
+}
+
+// SubSafe subtracts two vectors: c = a - b.
+// Returns a DimensionError, if sizes mismatch.
+func (a *CVector) SubSafe(b *CVector) (c *CVector, e error) {
+	g, e := a.gen().SubSafe(b.gen())
+	c = (*CVector)(g)
+	return
+}
+
+// CWiseProd computes the component-wise product of two vectors.
+// Dimension mismatch returns nil.
+func (a *CVector) CWiseProd(b *CVector) (c *CVector) {
+	c, _ = a.CWiseProdSafe(b)
+	return
+}
+
+// CWiseProdSafe computes the component-wise product of two vectors.
+// Returns a DimensionError, if sizes mismatch.
+func (a *CVector) CWiseProdSafe(b *CVector) (c *CVector, e error) {
+	g, e := a.gen().CWiseProdSafe(b.gen())
+	c = (*CVector)(g)
+	return
+}
+
+// Scale multiplies every element with factor
+func (a *CVector) Scale(factor complex128) (v *CVector) {
+	v = (*CVector)(a.gen().Scale(factor))
+	return
+}
+
+// ApplyFunc applies the function f to every element and returns the result
+func (a *CVector) ApplyFunc(f func(complex128) complex128) (v *CVector) {
+	v = (*CVector)(a.gen().ApplyFunc(f))
+	return
+}
+
+// Sum returns the sum of all elements
+func (a *CVector) Sum() complex128 {
+	return a.gen().Sum()
+}
+
+// Dot computes the Hermitian inner product sum conj(a_i) * b_i.
+// Dimension mismatch returns NaN.
+func (a *CVector) Dot(b *CVector) complex128 {
+	result, e := a.DotSafe(b)
+	if e != nil {
+		return cmplx.NaN()
+	}
+	return result
+}
+
+// DotSafe computes the Hermitian inner product sum conj(a_i) * b_i.
+// Returns a DimensionError, if sizes mismatch.
+func (a *CVector) DotSafe(b *CVector) (result complex128, e error) {
+	// check sizes
+	if a.Size() != b.Size() {
+		e = &DimensionError{Op: "Dot", A: a.shape(), B: b.shape()}
+		return
+	}
+	for i, z := range a.entries {
+		result += cmplx.Conj(z) * b.entries[i]
+	}
+	return
+}
+
+// gen returns a as generic vector without copying
+func (a *CVector) gen() *GenVector[complex128] {
+	return (*GenVector[complex128])(a)
+}
+
+// shape returns the size of a for error values
+func (a *CVector) shape() []int {
+	return []int{a.Size()}
+}
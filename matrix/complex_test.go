@@ -0,0 +1,200 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+// randCMat returns a random complex r x c matrix
+func randCMat(r, c int, rng *rand.Rand) *CMatrix {
+	m, _ := ZeroCMat(r, c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.Set(i, j, complex(rng.NormFloat64(), rng.NormFloat64()))
+		}
+	}
+	return m
+}
+
+// cmatClose fails, if a and b differ by more than tol in an entry
+func cmatClose(t *testing.T, name string, a, b *CMatrix, tol float64) {
+	t.Helper()
+	if a == nil || b == nil {
+		t.Fatalf("%s: nil matrix", name)
+	}
+	if a.Rows() != b.Rows() || a.Cols() != b.Cols() {
+		t.Fatalf("%s: dims %dx%d vs %dx%d", name, a.Rows(), a.Cols(), b.Rows(), b.Cols())
+	}
+	for i := 0; i < a.Rows(); i++ {
+		for j := 0; j < a.Cols(); j++ {
+			if cmplx.Abs(a.Get(i, j)-b.Get(i, j)) > tol {
+				t.Fatalf("%s: (%d,%d) %v vs %v", name, i, j, a.Get(i, j), b.Get(i, j))
+			}
+		}
+	}
+}
+
+func TestHermitianProducts(t *testing.T) {
+	rng := rand.New(rand.NewSource(16))
+	a := randCMat(4, 3, rng)
+	b := randCMat(4, 2, rng)
+	c := randCMat(5, 3, rng)
+	parts, _ := CMatFromParts(a.Real(), a.Imag())
+	tests := []struct {
+		name      string
+		got, want *CMatrix
+		tol       float64
+	}{
+		{"HMul", a.HMul(b), a.H().Mul(b), 1e-12},
+		{"MulH", a.MulH(c), a.Mul(c.H()), 1e-12},
+		{"H H", a.H().H(), a, 0},
+		{"CMatFromParts", parts, a, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmatClose(t, tt.name, tt.got, tt.want, tt.tol)
+		})
+	}
+	v := CVecFromSlice([]complex128{1i, 1, 2 - 1i, 0})
+	hv, hw := a.HMulVec(v), a.H().MulVec(v)
+	for i := 0; i < hv.Size(); i++ {
+		if cmplx.Abs(hv.Get(i)-hw.Get(i)) > 1e-12 {
+			t.Fatal("HMulVec")
+		}
+	}
+	if w := CVecFromSlice([]complex128{1i, 1}); w.Dot(w) != 2 {
+		t.Fatal("Hermitian inner product", w.Dot(w))
+	}
+}
+
+func TestCLU(t *testing.T) {
+	rng := rand.New(rand.NewSource(16))
+	for _, n := range []int{1, 2, 5, 10} {
+		s := randCMat(n, n, rng)
+		x := ZeroCVec(n)
+		for i := 0; i < n; i++ {
+			x.Set(i, complex(rng.NormFloat64(), rng.NormFloat64()))
+		}
+		sol, e := s.Solve(s.MulVec(x))
+		if e != nil {
+			t.Fatal(n, e)
+		}
+		for i := 0; i < n; i++ {
+			if cmplx.Abs(sol.Get(i)-x.Get(i)) > 1e-9 {
+				t.Fatal("Solve", n)
+			}
+		}
+		inv, e := s.Inverse()
+		if e != nil {
+			t.Fatal(n, e)
+		}
+		id, _ := IdCMat(n, n)
+		cmatClose(t, "Inverse", s.Mul(inv), id, 1e-9)
+	}
+	a, _ := CMatrixFromSlice([][]complex128{{1 + 1i, 2}, {3i, 4 - 1i}})
+	if cmplx.Abs(a.Det()-((1+1i)*(4-1i)-6i)) > 1e-14 {
+		t.Fatal(a.Det())
+	}
+	sing, _ := CMatrixFromSlice([][]complex128{{1i, 2i}, {1, 2}})
+	if _, e := sing.Inverse(); !errors.Is(e, ErrSingular) {
+		t.Fatal(e)
+	}
+}
+
+func TestHermitianUnitary(t *testing.T) {
+	rng := rand.New(rand.NewSource(16))
+	s := randCMat(5, 5, rng)
+	if !s.Add(s.H()).IsHermitian(1e-14) || s.IsHermitian(1e-3) {
+		t.Fatal("IsHermitian")
+	}
+	// the normalized DFT matrix is unitary
+	f, _ := ZeroCMat(4, 4)
+	for j := 0; j < 4; j++ {
+		for k := 0; k < 4; k++ {
+			f.Set(j, k, cmplx.Exp(complex(0, -2*math.Pi*float64(j*k)/4))/2)
+		}
+	}
+	if !f.IsUnitary(1e-12) || s.IsUnitary(1e-3) {
+		t.Fatal("IsUnitary")
+	}
+	if math.Abs(cmplx.Abs(f.Det())-1) > 1e-12 {
+		t.Fatal("Det", f.Det())
+	}
+}
+
+func TestComplexMethods(t *testing.T) {
+	a, _ := CMatrixFromSlice([][]complex128{{1 + 1i, 2}, {3, 4 - 2i}})
+	// the methods return complex types, not the generic ones
+	var c *CMatrix = a.CWiseProd(a)
+	if c.Get(0, 0) != 2i {
+		t.Fatal(c.Get(0, 0))
+	}
+	if c, e := a.CWiseProdSafe(a.T()); e != nil || c.Get(0, 1) != 6 {
+		t.Fatal(e)
+	}
+	c = a.ApplyFunc(func(z complex128) complex128 { return z + 1 })
+	if c.Get(1, 1) != 5-2i || c.Rows() != 2 || c.Cols() != 2 {
+		t.Fatal(c)
+	}
+	a.Set(0, 1, 5)
+	if z, e := a.GetSafe(0, 1); z != 5 || e != nil {
+		t.Fatal(z, e)
+	}
+	if e := a.SetSafe(2, 0, 1); !errors.Is(e, ErrIndexOutOfRange) {
+		t.Fatal(e)
+	}
+	if !cmplx.IsNaN(a.Get(9, 0)) || a.Mul(randCMat(3, 3, rand.New(rand.NewSource(16)))) != nil {
+		t.Fatal("invalid access")
+	}
+	v := CVecFromSlice([]complex128{1i, 2})
+	var s *CVector = v.CWiseProd(v)
+	if s.Get(0) != -1 || v.Sum() != 2+1i || v.Size() != 2 || len(v.Slice()) != 2 {
+		t.Fatal(s)
+	}
+	if x, e := a.HMulVecSafe(v); e != nil || x.Get(0) != (1-1i)*1i+3*2 {
+		t.Fatal(x, e)
+	}
+	if (*GenMatrix[complex128])(a).Get(0, 1) != 5 {
+		t.Fatal("conversion")
+	}
+}
+
+func TestComplexSafe(t *testing.T) {
+	a, _ := CMatrixFromSlice([][]complex128{{1, 2}, {3, 4}})
+	v := CVecFromSlice([]complex128{1i, 2})
+	w := CVecFromSlice([]complex128{1, 2, 3})
+	var de *DimensionError
+	tests := []struct {
+		name string
+		e    error
+	}{
+		{"AddSafe", second(v.AddSafe(w))},
+		{"SubSafe", second(v.SubSafe(w))},
+		{"HMulVecSafe", second(a.HMulVecSafe(w))},
+		{"AddSafe matrix", second(a.AddSafe(randCMat(1, 2, rand.New(rand.NewSource(16)))))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.As(tt.e, &de) {
+				t.Fatal(tt.e)
+			}
+		})
+	}
+	if _, e := ZeroCMat(0, 2); !errors.Is(e, ErrInvalidArgument) {
+		t.Fatal(e)
+	}
+	if _, e := CMatrixFromSlice([][]complex128{{1}, {1, 2}}); !errors.Is(e, ErrInvalidArgument) {
+		t.Fatal(e)
+	}
+}
+
+func TestHMulNaN(t *testing.T) {
+	a, _ := CMatrixFromSlice([][]complex128{{0}})
+	b, _ := CMatrixFromSlice([][]complex128{{complex(math.Inf(1), 0)}})
+	if c := a.HMul(b); !cmplx.IsNaN(c.Get(0, 0)) {
+		t.Fatal(c.Get(0, 0))
+	}
+}
@@ -11,18 +11,32 @@ import (
 // ZeroMat creates a zero matrix with r rows and c column
 func ZeroMat(r, c int) (m *Matrix, e error) {
 	if r <= 0 || c <= 0 {
-		e = fmt.Errorf("Invalid dimensions")
+		e = fmt.Errorf("%w: invalid dimensions %dx%d", ErrInvalidArgument, r, c)
 		return
 	}
 	// create Matrix
 	m = new(Matrix)
 	m.rows = r
 	m.cols = c
+	m.rstride = c
+	m.cstride = 1
 	// allocate array for entries
 	m.entries = make([]float64, r*c)
 	return
 }
 
+// matFromEntries creates a matrix with r rows and c columns, which
+// references the row-major entries without copying them
+func matFromEntries(r, c int, entries []float64) (m *Matrix) {
+	m = new(Matrix)
+	m.rows = r
+	m.cols = c
+	m.rstride = c
+	m.cstride = 1
+	m.entries = entries
+	return
+}
+
 // IdMat creates a identity matrix with r rows and c columns
 // The diagonal entries are set to 1
 func IdMat(r, c int) (m *Matrix, e error) {
@@ -50,20 +64,20 @@ func IdMat(r, c int) (m *Matrix, e error) {
 func MatrixFromSlice(slice [][]float64) (m *Matrix, e error) {
 	// check size of slice
 	if len(slice) < 1 {
-		e = fmt.Errorf("Error: empty slice")
+		e = fmt.Errorf("%w: empty slice", ErrInvalidArgument)
 		return
 	}
 	// reference value for col size
 	col := len(slice[0])
 	// check if empty col
 	if col == 0 {
-		e = fmt.Errorf("Error: empty column in slice")
+		e = fmt.Errorf("%w: empty column in slice", ErrInvalidArgument)
 		return
 	}
 	// inspect number of cols
 	for i := range slice {
 		if col != len(slice[i]) {
-			e = fmt.Errorf("Error: mismatching column lengths in slice")
+			e = fmt.Errorf("%w: mismatching column lengths in slice", ErrInvalidArgument)
 			return
 		}
 	}
@@ -89,6 +103,7 @@ func ZeroVec(size int) (v *Vector) {
 	// create vector
 	v = new(Vector)
 	v.entries = make([]float64, size)
+	v.inc = 1
 	return v
 }
 
@@ -104,3 +119,37 @@ func VecFromSlice(slice []float64) (v *Vector) {
 	copy(v.entries, slice)
 	return v
 }
+
+// DenseOf creates a dense copy of the matrix a.
+// Matrices with a ToDense method, like COO, CSR and CSC, are converted
+// by it instead of reading every entry with At.
+// Returns nil, if a has invalid dimensions
+func DenseOf(a Mat) (m *Matrix) {
+	switch d := a.(type) {
+	case *Matrix:
+		m = d.CopyMat()
+		return
+	case interface{ ToDense() *Matrix }:
+		m = d.ToDense()
+		return
+	}
+	r, c := a.Dims()
+	m, _ = ZeroMat(r, c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.entries[i*c+j] = a.At(i, j)
+		}
+	}
+	return
+}
+
+// asMatrix returns a itself, if it is a *Matrix, and a dense copy otherwise.
+// The result must not be modified.
+func asMatrix(a Mat) (m *Matrix) {
+	if d, ok := a.(*Matrix); ok {
+		m = d
+		return
+	}
+	m = DenseOf(a)
+	return
+}
@@ -0,0 +1,106 @@
+package matrix
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// toeplitz is a symmetric Toeplitz matrix, which only implements Mat
+type toeplitz struct {
+	n int
+	c []float64
+}
+
+func (t toeplitz) Dims() (int, int) { return t.n, t.n }
+
+func (t toeplitz) At(i, j int) float64 {
+	if i < j {
+		i, j = j, i
+	}
+	return t.c[i-j]
+}
+
+var (
+	_ MutableMat = (*Matrix)(nil)
+	_ MutableMat = (*CSR)(nil)
+	_ MutableMat = (*CSC)(nil)
+	_ MutableMat = (*COO)(nil)
+)
+
+func TestMatFactorizations(t *testing.T) {
+	tp := toeplitz{5, []float64{4, 1, 0.5, 0.2, 0.1}}
+	d := DenseOf(tp)
+	f, e := NewLU(tp)
+	if e != nil || math.Abs(f.Det()-d.Det()) > 1e-12 {
+		t.Fatal("LU", e)
+	}
+	s, e := NewSVD(tp, false)
+	if e != nil {
+		t.Fatal(e)
+	}
+	s2, _ := NewSVD(d, false)
+	vecClose(t, "SVD", s.Values(), s2.Values(), 1e-12)
+	if _, e := NewCholesky(tp); e != nil {
+		t.Fatal(e)
+	}
+	if _, e := NewEigenSym(tp); e != nil {
+		t.Fatal(e)
+	}
+	rng := rand.New(rand.NewSource(14))
+	a := randMat(6, 4, rng)
+	sp := CSRFromDense(a)
+	q, e := NewQR(sp)
+	if e != nil {
+		t.Fatal(e)
+	}
+	matClose(t, "QR", q.Q().Mul(q.R()), a, 1e-12)
+	b := randVec(6, rng)
+	x1, _, _, _ := LeastSquares(sp, b)
+	x2, _, _, _ := LeastSquares(a, b)
+	vecClose(t, "LeastSquares", x1, x2, 1e-12)
+}
+
+func TestDenseOf(t *testing.T) {
+	rng := rand.New(rand.NewSource(14))
+	c, _ := NewCOO(30, 30)
+	for k := 0; k < 200; k++ {
+		c.Append(rng.Intn(30), rng.Intn(30), rng.NormFloat64())
+	}
+	a := randMat(6, 4, rng)
+	view := a.BlockView(1, 1, 4, 3)
+	d := c.ToDense()
+	tests := []struct {
+		name string
+		m    Mat
+		want *Matrix
+	}{
+		{"Matrix", a, a},
+		{"view", view, view},
+		{"COO", c, d},
+		{"CSR", c.ToCSR(), d},
+		{"CSC", c.ToCSC(), d},
+		{"Mat", toeplitz{2, []float64{2, 1}}, eye2(2, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matClose(t, tt.name, DenseOf(tt.m), tt.want, 1e-15)
+		})
+	}
+	// DenseOf copies
+	e := DenseOf(a)
+	e.Set(0, 0, 42)
+	if a.Get(0, 0) == 42 {
+		t.Fatal("DenseOf shares the storage")
+	}
+	r, cols := a.Dims()
+	if r != 6 || cols != 4 || a.At(1, 2) != a.Get(1, 2) {
+		t.Fatal("Dims or At")
+	}
+}
+
+// eye2 returns the symmetric 2x2 matrix with diagonal d and off-diagonal o
+func eye2(d, o float64) *Matrix {
+	m, _ := MatrixFromSlice([][]float64{{d, o}, {o, d}})
+	return m
+}
@@ -0,0 +1,152 @@
+/*	This file implements the covariance and correlation of the columns
+	of a data matrix, whose rows are the observations
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Center returns the matrix c with the column means subtracted from
+// every row of a and the column means.
+func (a *Matrix) Center() (c *Matrix, means *Vector) {
+	means = a.ColMeans()
+	c = a.SubRowVec(means)
+	return
+}
+
+// Cov returns the cols x cols sample covariance matrix of the columns
+// of a (divisor rows-1), where every row of a is an observation.
+// Returns nil, if a has less than two rows.
+func (a *Matrix) Cov() (c *Matrix) {
+	c, _ = a.CovSafe()
+	return
+}
+
+// CovSafe returns the sample covariance matrix of the columns of a, like Cov.
+// Returns ErrInvalidArgument, if a has less than two rows.
+func (a *Matrix) CovSafe() (c *Matrix, e error) {
+	// check number of observations
+	if a.rows < 2 {
+		e = fmt.Errorf("%w: covariance of %d observations", ErrInvalidArgument, a.rows)
+		return
+	}
+	// the two pass algorithm doesn't suffer from a large offset
+	centered, _ := a.Center()
+	c = centered.TMul(centered)
+	c.symmetrize()
+	c.ScaleInPlace(1 / float64(a.rows-1))
+	return
+}
+
+// Corr returns the cols x cols Pearson correlation matrix of the columns
+// of a, where every row of a is an observation.
+// Rows and columns of constant columns or columns containing NaN are NaN.
+// Returns nil, if a has less than two rows.
+func (a *Matrix) Corr() (c *Matrix) {
+	c, _ = a.CorrSafe()
+	return
+}
+
+// CorrSafe returns the Pearson correlation matrix of the columns of a, like Corr.
+// Returns ErrInvalidArgument, if a has less than two rows.
+func (a *Matrix) CorrSafe() (c *Matrix, e error) {
+	c, e = a.CovSafe()
+	if e != nil {
+		return
+	}
+	covToCorr(c)
+	return
+}
+
+// SpearmanCorr returns the cols x cols Spearman rank correlation matrix of
+// the columns of a, i.e. the Pearson correlation of the ranks of the
+// observations in each column. Ties get the average of their ranks.
+// Rows and columns of constant columns or columns containing NaN are NaN.
+// Returns nil, if a has less than two rows.
+func (a *Matrix) SpearmanCorr() (c *Matrix) {
+	c, _ = a.SpearmanCorrSafe()
+	return
+}
+
+// SpearmanCorrSafe returns the Spearman rank correlation matrix of the
+// columns of a, like SpearmanCorr.
+// Returns ErrInvalidArgument, if a has less than two rows.
+func (a *Matrix) SpearmanCorrSafe() (c *Matrix, e error) {
+	// check number of observations
+	if a.rows < 2 {
+		e = fmt.Errorf("%w: correlation of %d observations", ErrInvalidArgument, a.rows)
+		return
+	}
+	// replace the observations by their ranks
+	r, _ := ZeroMat(a.rows, a.cols)
+	for j := 0; j < a.cols; j++ {
+		r.SetCol(j, rank(a.ColView(j)))
+	}
+	c, e = r.CorrSafe()
+	return
+}
+
+// symmetrize copies the lower triangle of the square matrix a to the upper one
+func (a *Matrix) symmetrize() {
+	for i := 0; i < a.rows; i++ {
+		for j := 0; j < i; j++ {
+			a.setEntry(j, i, a.getEntry(i, j))
+		}
+	}
+}
+
+// covToCorr scales the covariance matrix c in place to the correlation matrix
+func covToCorr(c *Matrix) {
+	n := c.rows
+	std := make([]float64, n)
+	for i := range std {
+		std[i] = math.Sqrt(c.getEntry(i, i))
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if !(std[i] > 0 && std[j] > 0) {
+				c.setEntry(i, j, math.NaN())
+				continue
+			}
+			// clamp rounding errors to the valid range
+			r := c.getEntry(i, j) / (std[i] * std[j])
+			c.setEntry(i, j, math.Max(-1, math.Min(1, r)))
+		}
+		if std[i] > 0 {
+			c.setEntry(i, i, 1)
+		}
+	}
+}
+
+// rank returns the ranks 1, ..., n of the elements of v,
+// tied elements get the average of their ranks.
+// All ranks are NaN, if an element is NaN.
+func rank(v *Vector) (r *Vector) {
+	n := v.Size()
+	r = ZeroVec(n)
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+		if math.IsNaN(v.at(i)) {
+			return r.ApplyFunc(func(float64) float64 { return math.NaN() })
+		}
+	}
+	sort.SliceStable(idx, func(k, l int) bool { return v.at(idx[k]) < v.at(idx[l]) })
+	for k := 0; k < n; {
+		l := k + 1
+		for l < n && v.at(idx[l]) == v.at(idx[k]) {
+			l++
+		}
+		// average of the ranks k+1, ..., l
+		avg := float64(k+l+1) / 2
+		for ; k < l; k++ {
+			r.entries[idx[k]] = avg
+		}
+	}
+	return
+}
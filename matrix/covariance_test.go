@@ -0,0 +1,90 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// correlatedData returns n samples of 4 variables, where the second
+// one depends on the first one and has a large offset
+func correlatedData(n int, rng *rand.Rand) *Matrix {
+	a := randMat(n, 4, rng)
+	for i := 0; i < n; i++ {
+		a.Set(i, 1, a.Get(i, 0)*2+0.1*a.Get(i, 1)+1e8)
+		a.Set(i, 3, a.Get(i, 3)*5)
+	}
+	return a
+}
+
+func TestCov(t *testing.T) {
+	rng := rand.New(rand.NewSource(24))
+	n := 200
+	a := correlatedData(n, rng)
+	c := a.Cov()
+	for j := 0; j < 4; j++ {
+		for k := 0; k < 4; k++ {
+			x, y := a.GetCol(j), a.GetCol(k)
+			mx, my := x.Mean(), y.Mean()
+			var s float64
+			for i := 0; i < n; i++ {
+				s += (x.Get(i) - mx) * (y.Get(i) - my)
+			}
+			s /= float64(n - 1)
+			if math.Abs(c.Get(j, k)-s) > 1e-9*math.Max(1, math.Abs(s)) || c.Get(j, k) != c.Get(k, j) {
+				t.Fatal(j, k, c.Get(j, k), s)
+			}
+		}
+	}
+	matClose(t, "view", a.T().T().Cov(), c, 1e-9)
+	r := a.Corr()
+	if r.Get(0, 0) != 1 || math.Abs(r.Get(0, 1)-c.Get(0, 1)/math.Sqrt(c.Get(0, 0)*c.Get(1, 1))) > 1e-12 {
+		t.Fatal("Corr")
+	}
+	cc, means := a.Center()
+	for j := 0; j < 4; j++ {
+		if math.Abs(cc.GetCol(j).Mean()) > 1e-7 || math.Abs(means.Get(j)-a.GetCol(j).Mean()) > 1e-7 {
+			t.Fatal("Center", j)
+		}
+	}
+}
+
+func TestSpearmanCorr(t *testing.T) {
+	m, _ := ZeroMat(5, 3)
+	for i := 0; i < 5; i++ {
+		x := float64(i)
+		m.Set(i, 0, x)
+		m.Set(i, 1, math.Exp(x))
+		m.Set(i, 2, 7)
+	}
+	ties, _ := MatrixFromSlice([][]float64{{1, 1}, {2, 3}, {2, 2}, {3, 4}})
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"monotone", m.SpearmanCorr().Get(0, 1), 1},
+		{"constant", m.SpearmanCorr().Get(0, 2), math.NaN()},
+		{"constant diagonal", m.SpearmanCorr().Get(2, 2), math.NaN()},
+		// scipy.stats.spearmanr([1, 2, 2, 3], [1, 3, 2, 4])
+		{"ties", ties.SpearmanCorr().Get(0, 1), 0.9486832980505138},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !floatClose(tt.got, tt.want, 1e-12) {
+				t.Fatal(tt.got)
+			}
+		})
+	}
+	vecClose(t, "rank", rank(VecFromSlice([]float64{3, 1, 3, 2})), VecFromSlice([]float64{3.5, 1, 3.5, 2}), 0)
+}
+
+func TestCovErrors(t *testing.T) {
+	one, _ := ZeroMat(1, 3)
+	if one.Cov() != nil {
+		t.Fatal("Cov of a single sample should be nil")
+	}
+	if _, e := one.CorrSafe(); !errors.Is(e, ErrInvalidArgument) {
+		t.Fatal(e)
+	}
+}
@@ -0,0 +1,113 @@
+/*	This file implements reading and writing of matrices and vectors
+	as delimited text (CSV, TSV)
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadCSV reads a matrix from delimited text, one row per record.
+// If opt.Header is set, the first record is returned as header.
+// opt may be nil for comma separated values without header.
+// Returns an error wrapping ErrFormat, if a field isn't a number
+// or the records have different lengths.
+func ReadCSV(r io.Reader, opt *CSVOptions) (m *Matrix, header []string, e error) {
+	cr := csv.NewReader(r)
+	if opt != nil && opt.Comma != 0 {
+		cr.Comma = opt.Comma
+	}
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		e = fmt.Errorf("%w: csv: %v", ErrFormat, err)
+		return
+	}
+	if opt != nil && opt.Header && len(records) > 0 {
+		header = records[0]
+		records = records[1:]
+	}
+	if len(records) == 0 {
+		header = nil
+		e = fmt.Errorf("%w: csv: no data", ErrFormat)
+		return
+	}
+	rows := len(records)
+	cols := len(records[0])
+	if header != nil && len(header) != cols {
+		e = fmt.Errorf("%w: csv: header has %d fields, data %d", ErrFormat, len(header), cols)
+		header = nil
+		return
+	}
+	res, _ := ZeroMat(rows, cols)
+	for i, record := range records {
+		for j, field := range record {
+			v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				header = nil
+				e = fmt.Errorf("%w: csv: record %d field %d: invalid value %q", ErrFormat, i+1, j+1, field)
+				return
+			}
+			res.entries[i*cols+j] = v
+		}
+	}
+	m = res
+	return
+}
+
+// ReadCSVVec reads a vector from delimited text with a single column
+// or a single row. opt may be nil for comma separated values without header.
+func ReadCSVVec(r io.Reader, opt *CSVOptions) (v *Vector, e error) {
+	m, _, e := ReadCSV(r, opt)
+	if e != nil {
+		return
+	}
+	if m.rows != 1 && m.cols != 1 {
+		e = fmt.Errorf("%w: csv: %dx%d matrix is not a vector", ErrFormat, m.rows, m.cols)
+		return
+	}
+	v = VecFromSlice(m.entries)
+	return
+}
+
+// WriteCSV writes the matrix a as delimited text, one row per record.
+// If opt.Names is set, it is written as header.
+// opt may be nil for comma separated values without header.
+// Returns a DimensionError, if the number of names doesn't match.
+func WriteCSV(w io.Writer, a Mat, opt *CSVOptions) (e error) {
+	r, c := a.Dims()
+	if opt != nil && opt.Names != nil && len(opt.Names) != c {
+		e = &DimensionError{Op: "WriteCSV", A: []int{r, c}, B: []int{len(opt.Names)}}
+		return
+	}
+	cw := csv.NewWriter(w)
+	if opt != nil && opt.Comma != 0 {
+		cw.Comma = opt.Comma
+	}
+	if opt != nil && opt.Names != nil {
+		cw.Write(opt.Names)
+	}
+	record := make([]string, c)
+	for i := 0; i < r; i++ {
+		for j := range record {
+			record[j] = strconv.FormatFloat(a.At(i, j), 'g', -1, 64)
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	e = cw.Error()
+	return
+}
+
+// WriteCSVVec writes the vector v as delimited text with a single column.
+// opt may be nil for comma separated values without header.
+func WriteCSVVec(w io.Writer, v *Vector, opt *CSVOptions) (e error) {
+	e = WriteCSV(w, v.Mat(), opt)
+	return
+}
@@ -0,0 +1,81 @@
+package matrix
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(17))
+	a := randMat(4, 3, rng)
+	tests := []struct {
+		name  string
+		write *CSVOptions
+		read  *CSVOptions
+	}{
+		{"default", nil, nil},
+		{"tsv", &CSVOptions{Comma: '\t'}, &CSVOptions{Comma: '\t'}},
+		{"header", &CSVOptions{Names: []string{"x", "y", "z"}}, &CSVOptions{Header: true}},
+		{"semicolon header", &CSVOptions{Comma: ';', Names: []string{"x", "y", "z"}}, &CSVOptions{Comma: ';', Header: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if e := WriteCSV(&buf, a, tt.write); e != nil {
+				t.Fatal(e)
+			}
+			b, names, e := ReadCSV(&buf, tt.read)
+			if e != nil {
+				t.Fatal(e)
+			}
+			matClose(t, tt.name, b, a, 0)
+			if tt.read != nil && tt.read.Header && (len(names) != 3 || names[2] != "z") {
+				t.Fatal(names)
+			}
+		})
+	}
+	v := randVec(5, rng)
+	var buf bytes.Buffer
+	WriteCSVVec(&buf, v, nil)
+	w, e := ReadCSVVec(&buf, nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	vecClose(t, "vector", w, v, 0)
+	// a single row is a vector too
+	w, _ = ReadCSVVec(strings.NewReader("1,2,3\n"), nil)
+	if w.Size() != 3 {
+		t.Fatal("row vector")
+	}
+}
+
+func TestCSVErrors(t *testing.T) {
+	m, _, e := ReadCSV(strings.NewReader("1, 2\n3, 4\n"), nil)
+	if e != nil || m.Get(1, 1) != 4 {
+		t.Fatal("leading spaces", e)
+	}
+	tests := []struct {
+		name, in string
+		opt      *CSVOptions
+	}{
+		{"ragged", "1,2\n3\n", nil},
+		{"only header", "a,b\n", &CSVOptions{Header: true}},
+		{"number", "1,x\n", nil},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, e := ReadCSV(strings.NewReader(tt.in), tt.opt); !errors.Is(e, ErrFormat) {
+				t.Fatal(e)
+			}
+		})
+	}
+	a, _ := ZeroMat(2, 3)
+	var buf bytes.Buffer
+	if e := WriteCSV(&buf, a, &CSVOptions{Names: []string{"x"}}); !errors.Is(e, ErrDimensionMismatch) {
+		t.Fatal(e)
+	}
+}
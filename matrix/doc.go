@@ -1,4 +1,15 @@
 /*	This package implements a matrix type and linear algebra operations
+
+	GenMatrix and GenVector are the generic types for element types like
+	float32, int or complex128 with the basic arithmetic. Matrix and Vector
+	are defined as GenMatrix[float64] and GenVector[float64] with the full
+	float64 API: views, factorizations, solvers, norms, statistics and
+	encodings. Both share the storage, so (*GenMatrix[float64])(m) and
+	(*Matrix)(g) convert without copying, while ToGenMat and FromGenMat
+	copy the entries to other element types.
+	CMatrix and CVector are defined as GenMatrix[complex128] and
+	GenVector[complex128] the same way and add complex linear algebra.
+
 	Author: Lino Telschow, tlino@student.ethz.ch
 */
 package matrix
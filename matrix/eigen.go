@@ -0,0 +1,611 @@
+/*	This file implements the eigenvalue decomposition of general (non-symmetric)
+	matrices via Hessenberg reduction and the shifted QR algorithm.
+	The algorithms are adapted from the EISPACK routines orthes and hqr2.
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"math"
+)
+
+// maxQRIterations limits the number of QR steps spent on a single eigenvalue
+const maxQRIterations = 100
+
+// Hessenberg computes the Hessenberg decomposition a = Q * H * Q^T,
+// where H is upper Hessenberg (zero below the first subdiagonal)
+// and Q is orthogonal. Returns an error, if a is not square.
+func Hessenberg(a Mat) (h, q *Matrix, e error) {
+	h, q, e = hessenberg(asMatrix(a))
+	return
+}
+
+// hessenberg implements Hessenberg for a dense matrix
+func hessenberg(a *Matrix) (h, q *Matrix, e error) {
+	// check dimensions
+	if a.rows != a.cols {
+		e = ErrNotSquare
+		return
+	}
+	n := a.rows
+	h = a.CopyMat()
+	q, _ = IdMat(n, n)
+	orthes(rowsOf(h), rowsOf(q), n)
+	// remove the remains of the Householder vectors
+	for i := 2; i < n; i++ {
+		for j := 0; j < i-1; j++ {
+			h.entries[i*n+j] = 0
+		}
+	}
+	return
+}
+
+// NewEigen computes the eigenvalues of the square matrix a and,
+// if vectors is set, its right eigenvectors.
+// Returns an error, if a is not square or the QR algorithm doesn't converge.
+func NewEigen(a Mat, vectors bool) (f *Eigen, e error) {
+	f, e = newEigen(asMatrix(a), vectors)
+	return
+}
+
+// newEigen implements NewEigen for a dense matrix
+func newEigen(a *Matrix, vectors bool) (f *Eigen, e error) {
+	// check dimensions
+	if a.rows != a.cols {
+		e = ErrNotSquare
+		return
+	}
+	n := a.rows
+	// reduce to Hessenberg form
+	h := a.CopyMat()
+	v, _ := IdMat(n, n)
+	hr := rowsOf(h)
+	vr := rowsOf(v)
+	orthes(hr, vr, n)
+	// reduce to real Schur form
+	f = new(Eigen)
+	f.re = ZeroVec(n)
+	f.im = ZeroVec(n)
+	if !hqr2(hr, vr, f.re.entries, f.im.entries, n, vectors) {
+		f = nil
+		e = ErrNoConvergence
+		return
+	}
+	if vectors {
+		normalizeEigenvectors(vr, f.im.entries, n)
+		f.vectors = v
+	}
+	return
+}
+
+// Values returns the real and imaginary parts of the eigenvalues.
+// Complex conjugate pairs are stored next to each other,
+// the one with positive imaginary part first.
+func (f *Eigen) Values() (re, im *Vector) {
+	re = f.re.CopyVec()
+	im = f.im.CopyVec()
+	return
+}
+
+// ComplexValues returns the eigenvalues as a slice of complex numbers.
+func (f *Eigen) ComplexValues() (c []complex128) {
+	c = make([]complex128, f.re.Size())
+	for i := range c {
+		c[i] = complex(f.re.entries[i], f.im.entries[i])
+	}
+	return
+}
+
+// Vectors returns the real and imaginary parts of the right eigenvectors.
+// The j-th column of re + i*im is a unit eigenvector of the j-th eigenvalue.
+// Returns nil, if the eigenvectors were not computed.
+func (f *Eigen) Vectors() (re, im *Matrix) {
+	if f.vectors == nil {
+		return
+	}
+	n := f.vectors.rows
+	v := f.vectors.entries
+	re, _ = ZeroMat(n, n)
+	im, _ = ZeroMat(n, n)
+	for j := 0; j < n; j++ {
+		switch {
+		case f.im.entries[j] > 0:
+			// first of a pair: v[:,j] + i*v[:,j+1]
+			for i := 0; i < n; i++ {
+				re.entries[i*n+j] = v[i*n+j]
+				im.entries[i*n+j] = v[i*n+j+1]
+			}
+		case f.im.entries[j] < 0:
+			// second of a pair: conjugate of the first
+			for i := 0; i < n; i++ {
+				re.entries[i*n+j] = v[i*n+j-1]
+				im.entries[i*n+j] = -v[i*n+j]
+			}
+		default:
+			for i := 0; i < n; i++ {
+				re.entries[i*n+j] = v[i*n+j]
+			}
+		}
+	}
+	return
+}
+
+// SpectralRadius returns the largest absolute value of the eigenvalues.
+func (f *Eigen) SpectralRadius() float64 {
+	var result float64 = 0
+	for i := range f.re.entries {
+		result = math.Max(result, math.Hypot(f.re.entries[i], f.im.entries[i]))
+	}
+	return result
+}
+
+// rowsOf returns the rows of a as slices sharing the storage of a.
+func rowsOf(a *Matrix) (rows [][]float64) {
+	rows = make([][]float64, a.rows)
+	for i := range rows {
+		rows[i] = a.entries[i*a.cols : (i+1)*a.cols]
+	}
+	return
+}
+
+// normalizeEigenvectors scales the eigenvectors (stored in real form)
+// to unit length. Complex pairs are scaled by their complex norm.
+func normalizeEigenvectors(v [][]float64, im []float64, n int) {
+	for j := 0; j < n; j++ {
+		if im[j] < 0 {
+			continue
+		}
+		var nrm float64 = 0
+		for i := 0; i < n; i++ {
+			nrm = math.Hypot(nrm, v[i][j])
+			if im[j] > 0 {
+				nrm = math.Hypot(nrm, v[i][j+1])
+			}
+		}
+		if nrm == 0 {
+			continue
+		}
+		for i := 0; i < n; i++ {
+			v[i][j] /= nrm
+			if im[j] > 0 {
+				v[i][j+1] /= nrm
+			}
+		}
+	}
+}
+
+// orthes reduces h to upper Hessenberg form by orthogonal similarity
+// transformations and accumulates them in v (which has to be the identity).
+func orthes(h, v [][]float64, n int) {
+	low := 0
+	high := n - 1
+	ort := make([]float64, n)
+	for m := low + 1; m <= high-1; m++ {
+		// scale column
+		var scale float64 = 0
+		for i := m; i <= high; i++ {
+			scale += math.Abs(h[i][m-1])
+		}
+		if scale == 0 {
+			continue
+		}
+		// compute Householder transformation
+		var hh float64 = 0
+		for i := high; i >= m; i-- {
+			ort[i] = h[i][m-1] / scale
+			hh += ort[i] * ort[i]
+		}
+		g := math.Sqrt(hh)
+		if ort[m] > 0 {
+			g = -g
+		}
+		hh -= ort[m] * g
+		ort[m] -= g
+		// apply Householder similarity transformation
+		// h = (I - u*u^T/hh) * h * (I - u*u^T/hh)
+		for j := m; j < n; j++ {
+			var f float64 = 0
+			for i := high; i >= m; i-- {
+				f += ort[i] * h[i][j]
+			}
+			f /= hh
+			for i := m; i <= high; i++ {
+				h[i][j] -= f * ort[i]
+			}
+		}
+		for i := 0; i <= high; i++ {
+			var f float64 = 0
+			for j := high; j >= m; j-- {
+				f += ort[j] * h[i][j]
+			}
+			f /= hh
+			for j := m; j <= high; j++ {
+				h[i][j] -= f * ort[j]
+			}
+		}
+		ort[m] *= scale
+		h[m][m-1] = scale * g
+	}
+	// accumulate transformations
+	for m := high - 1; m >= low+1; m-- {
+		if h[m][m-1] == 0 {
+			continue
+		}
+		for i := m + 1; i <= high; i++ {
+			ort[i] = h[i][m-1]
+		}
+		for j := m; j <= high; j++ {
+			var g float64 = 0
+			for i := m; i <= high; i++ {
+				g += ort[i] * v[i][j]
+			}
+			// double division avoids possible underflow
+			g = (g / ort[m]) / h[m][m-1]
+			for i := m; i <= high; i++ {
+				v[i][j] += g * ort[i]
+			}
+		}
+	}
+}
+
+// cdiv returns the complex division (xr + i*xi) / (yr + i*yi).
+func cdiv(xr, xi, yr, yi float64) (float64, float64) {
+	c := complex(xr, xi) / complex(yr, yi)
+	return real(c), imag(c)
+}
+
+// hqr2 reduces the Hessenberg matrix h to real Schur form by the shifted
+// double QR algorithm and stores the eigenvalues in d (real parts) and
+// e (imaginary parts). If vectors is set, the eigenvectors are computed
+// by back substitution and stored in v in real form.
+// Returns false, if the iteration didn't converge.
+func hqr2(h, v [][]float64, d, e []float64, nn int, vectors bool) bool {
+	n := nn - 1
+	low := 0
+	high := nn - 1
+	var exshift, p, q, r, s, z float64
+	var t, w, x, y float64
+	// compute matrix norm
+	var norm float64 = 0
+	for i := 0; i < nn; i++ {
+		for j := max(i-1, 0); j < nn; j++ {
+			norm += math.Abs(h[i][j])
+		}
+	}
+	// outer loop over eigenvalue index
+	iter := 0
+	for n >= low {
+		// look for single small sub-diagonal element
+		l := n
+		for l > low {
+			s = math.Abs(h[l-1][l-1]) + math.Abs(h[l][l])
+			if s == 0 {
+				s = norm
+			}
+			if math.Abs(h[l][l-1]) < eps*s {
+				break
+			}
+			l--
+		}
+		if l == n {
+			// one root found
+			h[n][n] += exshift
+			d[n] = h[n][n]
+			e[n] = 0
+			n--
+			iter = 0
+		} else if l == n-1 {
+			// two roots found
+			w = h[n][n-1] * h[n-1][n]
+			p = (h[n-1][n-1] - h[n][n]) / 2
+			q = p*p + w
+			z = math.Sqrt(math.Abs(q))
+			h[n][n] += exshift
+			h[n-1][n-1] += exshift
+			x = h[n][n]
+			if q >= 0 {
+				// real pair
+				if p >= 0 {
+					z = p + z
+				} else {
+					z = p - z
+				}
+				d[n-1] = x + z
+				d[n] = d[n-1]
+				if z != 0 {
+					d[n] = x - w/z
+				}
+				e[n-1] = 0
+				e[n] = 0
+				x = h[n][n-1]
+				s = math.Abs(x) + math.Abs(z)
+				p = x / s
+				q = z / s
+				r = math.Sqrt(p*p + q*q)
+				p /= r
+				q /= r
+				// row modification
+				for j := n - 1; j < nn; j++ {
+					z = h[n-1][j]
+					h[n-1][j] = q*z + p*h[n][j]
+					h[n][j] = q*h[n][j] - p*z
+				}
+				// column modification
+				for i := 0; i <= n; i++ {
+					z = h[i][n-1]
+					h[i][n-1] = q*z + p*h[i][n]
+					h[i][n] = q*h[i][n] - p*z
+				}
+				// accumulate transformations
+				for i := low; i <= high; i++ {
+					z = v[i][n-1]
+					v[i][n-1] = q*z + p*v[i][n]
+					v[i][n] = q*v[i][n] - p*z
+				}
+			} else {
+				// complex pair
+				d[n-1] = x + p
+				d[n] = x + p
+				e[n-1] = z
+				e[n] = -z
+			}
+			n -= 2
+			iter = 0
+		} else {
+			// no convergence yet
+			x = h[n][n]
+			y = 0
+			w = 0
+			if l < n {
+				y = h[n-1][n-1]
+				w = h[n][n-1] * h[n-1][n]
+			}
+			// Wilkinson's original ad hoc shift
+			if iter == 10 {
+				exshift += x
+				for i := low; i <= n; i++ {
+					h[i][i] -= x
+				}
+				s = math.Abs(h[n][n-1]) + math.Abs(h[n-1][n-2])
+				x = 0.75 * s
+				y = x
+				w = -0.4375 * s * s
+			}
+			// MATLAB's new ad hoc shift
+			if iter == 30 {
+				s = (y - x) / 2
+				s = s*s + w
+				if s > 0 {
+					s = math.Sqrt(s)
+					if y < x {
+						s = -s
+					}
+					s = x - w/((y-x)/2+s)
+					for i := low; i <= n; i++ {
+						h[i][i] -= s
+					}
+					exshift += s
+					x = 0.964
+					y = x
+					w = x
+				}
+			}
+			iter++
+			if iter > maxQRIterations {
+				return false
+			}
+			// look for two consecutive small sub-diagonal elements
+			m := n - 2
+			for m >= l {
+				z = h[m][m]
+				r = x - z
+				s = y - z
+				p = (r*s-w)/h[m+1][m] + h[m][m+1]
+				q = h[m+1][m+1] - z - r - s
+				r = h[m+2][m+1]
+				s = math.Abs(p) + math.Abs(q) + math.Abs(r)
+				p /= s
+				q /= s
+				r /= s
+				if m == l {
+					break
+				}
+				if math.Abs(h[m][m-1])*(math.Abs(q)+math.Abs(r)) <
+					eps*(math.Abs(p)*(math.Abs(h[m-1][m-1])+math.Abs(z)+math.Abs(h[m+1][m+1]))) {
+					break
+				}
+				m--
+			}
+			for i := m + 2; i <= n; i++ {
+				h[i][i-2] = 0
+				if i > m+2 {
+					h[i][i-3] = 0
+				}
+			}
+			// double QR step involving rows l:n and columns m:n
+			for k := m; k <= n-1; k++ {
+				notlast := k != n-1
+				if k != m {
+					p = h[k][k-1]
+					q = h[k+1][k-1]
+					r = 0
+					if notlast {
+						r = h[k+2][k-1]
+					}
+					x = math.Abs(p) + math.Abs(q) + math.Abs(r)
+					if x == 0 {
+						continue
+					}
+					p /= x
+					q /= x
+					r /= x
+				}
+				s = math.Sqrt(p*p + q*q + r*r)
+				if p < 0 {
+					s = -s
+				}
+				if s == 0 {
+					continue
+				}
+				if k != m {
+					h[k][k-1] = -s * x
+				} else if l != m {
+					h[k][k-1] = -h[k][k-1]
+				}
+				p += s
+				x = p / s
+				y = q / s
+				z = r / s
+				q /= p
+				r /= p
+				// row modification
+				for j := k; j < nn; j++ {
+					p = h[k][j] + q*h[k+1][j]
+					if notlast {
+						p += r * h[k+2][j]
+						h[k+2][j] -= p * z
+					}
+					h[k][j] -= p * x
+					h[k+1][j] -= p * y
+				}
+				// column modification
+				for i := 0; i <= min(n, k+3); i++ {
+					p = x*h[i][k] + y*h[i][k+1]
+					if notlast {
+						p += z * h[i][k+2]
+						h[i][k+2] -= p * r
+					}
+					h[i][k] -= p
+					h[i][k+1] -= p * q
+				}
+				// accumulate transformations
+				for i := low; i <= high; i++ {
+					p = x*v[i][k] + y*v[i][k+1]
+					if notlast {
+						p += z * v[i][k+2]
+						v[i][k+2] -= p * r
+					}
+					v[i][k] -= p
+					v[i][k+1] -= p * q
+				}
+			}
+		}
+	}
+	if !vectors || norm == 0 {
+		return true
+	}
+	// back substitute to find vectors of upper triangular form
+	for n = nn - 1; n >= 0; n-- {
+		p = d[n]
+		q = e[n]
+		if q == 0 {
+			// real vector
+			l := n
+			h[n][n] = 1
+			for i := n - 1; i >= 0; i-- {
+				w = h[i][i] - p
+				r = 0
+				for j := l; j <= n; j++ {
+					r += h[i][j] * h[j][n]
+				}
+				if e[i] < 0 {
+					z = w
+					s = r
+					continue
+				}
+				l = i
+				if e[i] == 0 {
+					if w != 0 {
+						h[i][n] = -r / w
+					} else {
+						h[i][n] = -r / (eps * norm)
+					}
+				} else {
+					// solve real equations
+					x = h[i][i+1]
+					y = h[i+1][i]
+					q = (d[i]-p)*(d[i]-p) + e[i]*e[i]
+					t = (x*s - z*r) / q
+					h[i][n] = t
+					if math.Abs(x) > math.Abs(z) {
+						h[i+1][n] = (-r - w*t) / x
+					} else {
+						h[i+1][n] = (-s - y*t) / z
+					}
+				}
+				// overflow control
+				t = math.Abs(h[i][n])
+				if (eps*t)*t > 1 {
+					for j := i; j <= n; j++ {
+						h[j][n] /= t
+					}
+				}
+			}
+		} else if q < 0 {
+			// complex vector, last component chosen imaginary
+			l := n - 1
+			if math.Abs(h[n][n-1]) > math.Abs(h[n-1][n]) {
+				h[n-1][n-1] = q / h[n][n-1]
+				h[n-1][n] = -(h[n][n] - p) / h[n][n-1]
+			} else {
+				h[n-1][n-1], h[n-1][n] = cdiv(0, -h[n-1][n], h[n-1][n-1]-p, q)
+			}
+			h[n][n-1] = 0
+			h[n][n] = 1
+			for i := n - 2; i >= 0; i-- {
+				var ra, sa float64 = 0, 0
+				for j := l; j <= n; j++ {
+					ra += h[i][j] * h[j][n-1]
+					sa += h[i][j] * h[j][n]
+				}
+				w = h[i][i] - p
+				if e[i] < 0 {
+					z = w
+					r = ra
+					s = sa
+					continue
+				}
+				l = i
+				if e[i] == 0 {
+					h[i][n-1], h[i][n] = cdiv(-ra, -sa, w, q)
+				} else {
+					// solve complex equations
+					x = h[i][i+1]
+					y = h[i+1][i]
+					vr := (d[i]-p)*(d[i]-p) + e[i]*e[i] - q*q
+					vi := (d[i] - p) * 2 * q
+					if vr == 0 && vi == 0 {
+						vr = eps * norm * (math.Abs(w) + math.Abs(q) + math.Abs(x) + math.Abs(y) + math.Abs(z))
+					}
+					h[i][n-1], h[i][n] = cdiv(x*r-z*ra+q*sa, x*s-z*sa-q*ra, vr, vi)
+					if math.Abs(x) > math.Abs(z)+math.Abs(q) {
+						h[i+1][n-1] = (-ra - w*h[i][n-1] + q*h[i][n]) / x
+						h[i+1][n] = (-sa - w*h[i][n] - q*h[i][n-1]) / x
+					} else {
+						h[i+1][n-1], h[i+1][n] = cdiv(-r-y*h[i][n-1], -s-y*h[i][n], z, q)
+					}
+				}
+				// overflow control
+				t = math.Max(math.Abs(h[i][n-1]), math.Abs(h[i][n]))
+				if (eps*t)*t > 1 {
+					for j := i; j <= n; j++ {
+						h[j][n-1] /= t
+						h[j][n] /= t
+					}
+				}
+			}
+		}
+	}
+	// back transformation to get eigenvectors of original matrix
+	for j := nn - 1; j >= low; j-- {
+		for i := low; i <= high; i++ {
+			z = 0
+			for k := low; k <= min(j, high); k++ {
+				z += v[i][k] * h[k][j]
+			}
+			v[i][j] = z
+		}
+	}
+	return true
+}
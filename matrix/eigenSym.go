@@ -0,0 +1,141 @@
+/*	This file implements the eigenvalue decomposition of symmetric matrices
+	with the cyclic Jacobi method
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"math"
+	"sort"
+)
+
+// maxJacobiSweeps limits the number of sweeps of the Jacobi method
+const maxJacobiSweeps = 100
+
+// NewEigenSym computes the eigenvalues and eigenvectors of the symmetric
+// matrix a. Only the lower triangle of a is read.
+// Returns an error, if a is not square or the iteration doesn't converge.
+func NewEigenSym(a Mat) (f *EigenSym, e error) {
+	f, e = newEigenSym(asMatrix(a))
+	return
+}
+
+// newEigenSym implements NewEigenSym for a dense matrix
+func newEigenSym(a *Matrix) (f *EigenSym, e error) {
+	// check dimensions
+	if a.rows != a.cols {
+		e = ErrNotSquare
+		return
+	}
+	n := a.rows
+	// symmetric working copy built from the lower triangle
+	w, _ := ZeroMat(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			w.entries[i*n+j] = a.getEntry(i, j)
+			w.entries[j*n+i] = a.getEntry(i, j)
+		}
+	}
+	v, _ := IdMat(n, n)
+	if !jacobiEigen(w.entries, v.entries, n) {
+		e = ErrNoConvergence
+		return
+	}
+	// sort eigenvalues ascending and permute eigenvectors accordingly
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return w.entries[order[i]*n+order[i]] < w.entries[order[j]*n+order[j]]
+	})
+	f = new(EigenSym)
+	f.values = ZeroVec(n)
+	f.vectors, _ = ZeroMat(n, n)
+	for k, idx := range order {
+		f.values.entries[k] = w.entries[idx*n+idx]
+		for i := 0; i < n; i++ {
+			f.vectors.entries[i*n+k] = v.entries[i*n+idx]
+		}
+	}
+	return
+}
+
+// Values returns the eigenvalues in ascending order.
+func (f *EigenSym) Values() (v *Vector) {
+	v = f.values.CopyVec()
+	return
+}
+
+// Vectors returns the orthonormal eigenvectors as columns of a matrix.
+// The j-th column belongs to the j-th eigenvalue of Values.
+func (f *EigenSym) Vectors() (m *Matrix) {
+	m = f.vectors.CopyMat()
+	return
+}
+
+// jacobiEigen diagonalizes the symmetric n x n matrix a in place
+// and accumulates the rotations in v.
+// Returns false, if the method didn't converge.
+func jacobiEigen(a, v []float64, n int) bool {
+	for sweep := 0; sweep < maxJacobiSweeps; sweep++ {
+		// compute off-diagonal and total mass
+		var off, total float64 = 0, 0
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				x := a[i*n+j] * a[i*n+j]
+				total += x
+				if i != j {
+					off += x
+				}
+			}
+		}
+		if off <= eps*eps*total {
+			return true
+		}
+		// one sweep over all off-diagonal entries
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				apq := a[p*n+q]
+				if apq == 0 {
+					continue
+				}
+				// compute rotation, which annihilates a[p][q]
+				tau := (a[q*n+q] - a[p*n+p]) / (2 * apq)
+				var t float64
+				if tau >= 0 {
+					t = 1 / (tau + math.Sqrt(1+tau*tau))
+				} else {
+					t = -1 / (-tau + math.Sqrt(1+tau*tau))
+				}
+				c := 1 / math.Sqrt(1+t*t)
+				s := t * c
+				// a = a * J
+				for k := 0; k < n; k++ {
+					akp := a[k*n+p]
+					akq := a[k*n+q]
+					a[k*n+p] = c*akp - s*akq
+					a[k*n+q] = s*akp + c*akq
+				}
+				// a = J^T * a
+				for k := 0; k < n; k++ {
+					apk := a[p*n+k]
+					aqk := a[q*n+k]
+					a[p*n+k] = c*apk - s*aqk
+					a[q*n+k] = s*apk + c*aqk
+				}
+				a[p*n+q] = 0
+				a[q*n+p] = 0
+				// v = v * J
+				for k := 0; k < n; k++ {
+					vkp := v[k*n+p]
+					vkq := v[k*n+q]
+					v[k*n+p] = c*vkp - s*vkq
+					v[k*n+q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package matrix
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// diagMat returns the diagonal matrix with the elements of v
+func diagMat(v *Vector) *Matrix {
+	m, _ := ZeroMat(v.Size(), v.Size())
+	for i := 0; i < v.Size(); i++ {
+		m.Set(i, i, v.Get(i))
+	}
+	return m
+}
+
+func TestEigenSym(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	for _, n := range []int{1, 2, 5, 12} {
+		g := randMat(n, n, rng)
+		a := g.Add(naiveT(g))
+		f, e := NewEigenSym(a)
+		if e != nil {
+			t.Fatal(n, e)
+		}
+		v, l := f.Vectors(), f.Values()
+		matClose(t, "V L V^T", v.Mul(diagMat(l)).MulT(v), a, 1e-10)
+		id, _ := IdMat(n, n)
+		matClose(t, "V^T V", v.TMul(v), id, 1e-12)
+		for i := 1; i < n; i++ {
+			if l.Get(i) < l.Get(i-1) {
+				t.Fatal("eigenvalues not sorted")
+			}
+		}
+	}
+}
+
+func TestEigenSymKnown(t *testing.T) {
+	a, _ := MatrixFromSlice([][]float64{{2, -1, 0}, {-1, 2, -1}, {0, -1, 2}})
+	f, e := NewEigenSym(a)
+	if e != nil {
+		t.Fatal(e)
+	}
+	// eigenvalues 2 - sqrt(2), 2 and 2 + sqrt(2)
+	want := VecFromSlice([]float64{0.5857864376269049, 2, 3.414213562373095})
+	vecClose(t, "Values", f.Values(), want, 1e-12)
+}
@@ -0,0 +1,97 @@
+package matrix
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+func TestHessenberg(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	for _, n := range []int{1, 2, 3, 6, 15} {
+		a := randMat(n, n, rng)
+		h, q, e := Hessenberg(a)
+		if e != nil {
+			t.Fatal(n, e)
+		}
+		matClose(t, "Q H Q^T", q.Mul(h).MulT(q), a, 1e-10)
+		id, _ := IdMat(n, n)
+		matClose(t, "Q^T Q", q.TMul(q), id, 1e-12)
+		for i := 2; i < n; i++ {
+			for j := 0; j < i-1; j++ {
+				if h.Get(i, j) != 0 {
+					t.Fatal("H not upper Hessenberg")
+				}
+			}
+		}
+	}
+}
+
+func TestEigen(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	for _, n := range []int{1, 2, 3, 6, 15} {
+		a := randMat(n, n, rng)
+		f, e := NewEigen(a, true)
+		if e != nil {
+			t.Fatal(n, e)
+		}
+		vals := f.ComplexValues()
+		re, im := f.Vectors()
+		var trace, sum float64
+		for j := 0; j < n; j++ {
+			trace += a.Get(j, j)
+			sum += real(vals[j])
+			// check A * v = lambda * v for the normalized eigenvector v
+			var nrm float64
+			for i := 0; i < n; i++ {
+				var av complex128
+				for k := 0; k < n; k++ {
+					av += complex(a.Get(i, k), 0) * complex(re.Get(k, j), im.Get(k, j))
+				}
+				vi := complex(re.Get(i, j), im.Get(i, j))
+				if d := cmplx.Abs(av - vals[j]*vi); d > 1e-9 {
+					t.Fatalf("n=%d eigenpair %d: residual %v", n, j, d)
+				}
+				nrm += real(vi * cmplx.Conj(vi))
+			}
+			if math.Abs(nrm-1) > 1e-12 {
+				t.Fatal("eigenvector not normalized", nrm)
+			}
+		}
+		if math.Abs(trace-sum) > 1e-9 {
+			t.Fatal("sum of eigenvalues", sum, "trace", trace)
+		}
+	}
+}
+
+func TestEigenKnown(t *testing.T) {
+	tests := []struct {
+		name   string
+		a      [][]float64
+		re, im []float64
+		radius float64
+	}{
+		{"rotation", [][]float64{{0, -2}, {2, 0}}, []float64{0, 0}, []float64{2, -2}, 2},
+		{"triangular", [][]float64{{3, 1}, {0, -1}}, []float64{3, -1}, []float64{0, 0}, 3},
+		{"defective", [][]float64{{1, 1, 0}, {0, 1, 1}, {0, 0, 1}}, []float64{1, 1, 1}, []float64{0, 0, 0}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := MatrixFromSlice(tt.a)
+			f, e := NewEigen(a, false)
+			if e != nil {
+				t.Fatal(e)
+			}
+			re, im := f.Values()
+			vecClose(t, "re", re, VecFromSlice(tt.re), 1e-5)
+			vecClose(t, "im", im, VecFromSlice(tt.im), 1e-5)
+			if math.Abs(f.SpectralRadius()-tt.radius) > 1e-5 {
+				t.Fatal(f.SpectralRadius())
+			}
+			if r, _ := f.Vectors(); r != nil {
+				t.Fatal("vectors without request")
+			}
+		})
+	}
+}
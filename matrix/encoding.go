@@ -0,0 +1,229 @@
+/*	This file implements the json, binary and gob encodings of matrices
+	and vectors
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// version of the binary encoding
+const binaryVersion = 1
+
+// kinds of the binary encoding
+const (
+	binaryMatrix = 'M'
+	binaryVector = 'V'
+)
+
+// jsonMatrix is the json representation of a matrix,
+// data holds the entries in row-major order
+type jsonMatrix struct {
+	Rows int       `json:"rows"`
+	Cols int       `json:"cols"`
+	Data []float64 `json:"data"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// A matrix is encoded as {"rows": r, "cols": c, "data": [row-major entries]}.
+// Returns an error, if m is empty or an entry is NaN or infinite.
+func (m Matrix) MarshalJSON() ([]byte, error) {
+	data, e := m.rowMajor()
+	if e != nil {
+		return nil, e
+	}
+	return json.Marshal(jsonMatrix{Rows: m.rows, Cols: m.cols, Data: data})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Returns an error wrapping ErrFormat, if the data doesn't match the shape.
+func (m *Matrix) UnmarshalJSON(b []byte) (e error) {
+	var j jsonMatrix
+	if e = json.Unmarshal(b, &j); e != nil {
+		return
+	}
+	// check the shape before building on the data, rows*cols may overflow
+	if j.Rows <= 0 || j.Cols <= 0 || len(j.Data)/j.Cols != j.Rows || len(j.Data)%j.Cols != 0 {
+		e = fmt.Errorf("%w: json: %d entries for %dx%d matrix", ErrFormat, len(j.Data), j.Rows, j.Cols)
+		return
+	}
+	*m = *matFromEntries(j.Rows, j.Cols, j.Data)
+	return
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// A vector is encoded as array of its elements.
+// Returns an error, if an element is NaN or infinite.
+func (v Vector) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Slice())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Returns an error wrapping ErrFormat for an empty array.
+func (v *Vector) UnmarshalJSON(b []byte) (e error) {
+	var s []float64
+	if e = json.Unmarshal(b, &s); e != nil {
+		return
+	}
+	if len(s) == 0 {
+		e = fmt.Errorf("%w: json: empty vector", ErrFormat)
+		return
+	}
+	*v = *VecFromSlice(s)
+	return
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The encoding is a version byte, the kind 'M', rows and cols as
+// little-endian uint64 and the entries in row-major order as
+// little-endian float64.
+// Returns an error, if m is empty.
+func (m Matrix) MarshalBinary() ([]byte, error) {
+	data, e := m.rowMajor()
+	if e != nil {
+		return nil, e
+	}
+	return encodeBinary(binaryMatrix, []int{m.rows, m.cols}, data), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// Returns an error wrapping ErrFormat, if the data is malformed.
+func (m *Matrix) UnmarshalBinary(b []byte) (e error) {
+	dims, data, e := decodeBinary(binaryMatrix, 2, b)
+	if e != nil {
+		return
+	}
+	if dims[0] == 0 || dims[1] == 0 {
+		e = fmt.Errorf("%w: binary: invalid dimensions %v", ErrFormat, dims)
+		return
+	}
+	*m = *matFromEntries(dims[0], dims[1], data)
+	return
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The encoding is a version byte, the kind 'V', the size as
+// little-endian uint64 and the elements as little-endian float64.
+func (v Vector) MarshalBinary() ([]byte, error) {
+	return encodeBinary(binaryVector, []int{v.Size()}, v.Slice()), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// Returns an error wrapping ErrFormat, if the data is malformed.
+func (v *Vector) UnmarshalBinary(b []byte) (e error) {
+	_, data, e := decodeBinary(binaryVector, 1, b)
+	if e != nil {
+		return
+	}
+	if len(data) == 0 {
+		e = fmt.Errorf("%w: binary: empty vector", ErrFormat)
+		return
+	}
+	*v = Vector{entries: data, inc: 1}
+	return
+}
+
+// GobEncode implements the gob.GobEncoder interface
+// with the binary encoding.
+func (m Matrix) GobEncode() ([]byte, error) {
+	return m.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (m *Matrix) GobDecode(b []byte) error {
+	return m.UnmarshalBinary(b)
+}
+
+// GobEncode implements the gob.GobEncoder interface
+// with the binary encoding.
+func (v Vector) GobEncode() ([]byte, error) {
+	return v.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (v *Vector) GobDecode(b []byte) error {
+	return v.UnmarshalBinary(b)
+}
+
+// rowMajor returns the entries of m in row-major order,
+// views are copied.
+// Returns ErrInvalidArgument for an empty matrix, e.g. the zero value.
+func (m *Matrix) rowMajor() (s []float64, e error) {
+	// check size
+	if m.rows <= 0 || m.cols <= 0 {
+		e = fmt.Errorf("%w: encoding of an empty matrix", ErrInvalidArgument)
+		return
+	}
+	if m.isContiguous() {
+		s = m.entries[:m.rows*m.cols]
+		return
+	}
+	s = m.CopyMat().entries
+	return
+}
+
+// encodeBinary returns the binary encoding of the given kind
+func encodeBinary(kind byte, dims []int, data []float64) (b []byte) {
+	b = make([]byte, 2+8*len(dims)+8*len(data))
+	b[0] = binaryVersion
+	b[1] = kind
+	p := 2
+	for _, d := range dims {
+		binary.LittleEndian.PutUint64(b[p:], uint64(d))
+		p += 8
+	}
+	for _, x := range data {
+		binary.LittleEndian.PutUint64(b[p:], math.Float64bits(x))
+		p += 8
+	}
+	return
+}
+
+// decodeBinary decodes the binary encoding of the given kind
+// with nDims dimensions
+func decodeBinary(kind byte, nDims int, b []byte) (dims []int, data []float64, e error) {
+	// check header
+	if len(b) < 2+8*nDims {
+		e = fmt.Errorf("%w: binary: truncated header", ErrFormat)
+		return
+	}
+	if b[0] != binaryVersion {
+		e = fmt.Errorf("%w: binary: unsupported version %d", ErrFormat, b[0])
+		return
+	}
+	if b[1] != kind {
+		e = fmt.Errorf("%w: binary: kind %q, expected %q", ErrFormat, b[1], kind)
+		return
+	}
+	// read dimensions
+	p := 2
+	n := uint64(1)
+	dims = make([]int, nDims)
+	for k := range dims {
+		d := binary.LittleEndian.Uint64(b[p:])
+		p += 8
+		// the data needs 8*n*d bytes, check without overflow
+		if d > 0 && n > uint64(len(b))/8/d {
+			e = fmt.Errorf("%w: binary: invalid dimension %d", ErrFormat, d)
+			return
+		}
+		dims[k] = int(d)
+		n *= d
+	}
+	// check length
+	if uint64(len(b)-p) != 8*n {
+		e = fmt.Errorf("%w: binary: %d bytes for %d entries", ErrFormat, len(b)-p, n)
+		return
+	}
+	data = make([]float64, n)
+	for k := range data {
+		data[k] = math.Float64frombits(binary.LittleEndian.Uint64(b[p:]))
+		p += 8
+	}
+	return
+}
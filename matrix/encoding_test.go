@@ -0,0 +1,116 @@
+package matrix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+type holder struct {
+	A *Matrix
+	V *Vector
+	B Matrix
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(19))
+	a := randMat(3, 4, rng)
+	h := holder{A: a.T(), V: a.ColView(1), B: *a}
+	tests := []struct {
+		name   string
+		encode func(holder) ([]byte, error)
+		decode func([]byte, *holder) error
+	}{
+		{"json", func(h holder) ([]byte, error) { return json.Marshal(h) },
+			func(b []byte, h *holder) error { return json.Unmarshal(b, h) }},
+		{"gob", func(h holder) ([]byte, error) {
+			var buf bytes.Buffer
+			e := gob.NewEncoder(&buf).Encode(h)
+			return buf.Bytes(), e
+		}, func(b []byte, h *holder) error { return gob.NewDecoder(bytes.NewReader(b)).Decode(h) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, e := tt.encode(h)
+			if e != nil {
+				t.Fatal(e)
+			}
+			var got holder
+			if e := tt.decode(b, &got); e != nil {
+				t.Fatal(e)
+			}
+			matClose(t, "view", got.A, naiveT(a), 0)
+			matClose(t, "value", &got.B, a, 0)
+			vecClose(t, "vector", got.V, a.GetCol(1), 0)
+		})
+	}
+}
+
+func TestBinaryEncoding(t *testing.T) {
+	rng := rand.New(rand.NewSource(19))
+	v := randVec(5, rng)
+	b, _ := v.MarshalBinary()
+	if len(b) != 2+8+40 || b[0] != 1 || b[1] != 'V' {
+		t.Fatal("layout")
+	}
+	var w Vector
+	if e := w.UnmarshalBinary(b); e != nil {
+		t.Fatal(e)
+	}
+	vecClose(t, "vector", &w, v, 0)
+	a := randMat(3, 2, rng).T()
+	b, _ = a.MarshalBinary()
+	var m Matrix
+	if e := m.UnmarshalBinary(b); e != nil {
+		t.Fatal(e)
+	}
+	matClose(t, "matrix", &m, a, 0)
+}
+
+func TestDecodingErrors(t *testing.T) {
+	v, _ := VecFromSlice([]float64{1, 2}).MarshalBinary()
+	huge := make([]byte, 18)
+	huge[0], huge[1] = 1, 'M'
+	binary.LittleEndian.PutUint64(huge[2:], 1<<32)
+	binary.LittleEndian.PutUint64(huge[10:], 1<<32)
+	var m Matrix
+	var w Vector
+	tests := []struct {
+		name string
+		e    error
+	}{
+		{"kind", m.UnmarshalBinary(v)},
+		{"truncated", m.UnmarshalBinary(v[:12])},
+		{"huge", m.UnmarshalBinary(huge)},
+		{"empty vector", w.UnmarshalBinary(encodeBinary('V', []int{0}, nil))},
+		{"json empty vector", json.Unmarshal([]byte(`[]`), &w)},
+		{"json huge", json.Unmarshal([]byte(`{"rows":100000,"cols":100000,"data":[]}`), &m)},
+		{"json overflow", json.Unmarshal([]byte(`{"rows":4294967296,"cols":4294967296,"data":[]}`), &m)},
+		{"json empty", json.Unmarshal([]byte(`{"rows":0,"cols":0,"data":[]}`), &m)},
+		{"json shape", json.Unmarshal([]byte(`{"rows":2,"cols":2,"data":[1,2,3]}`), &m)},
+		{"json negative", json.Unmarshal([]byte(`{"rows":-1,"cols":-2,"data":[1,2]}`), &m)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.e, ErrFormat) {
+				t.Fatal(tt.e)
+			}
+		})
+	}
+	if e := json.Unmarshal([]byte(`{"rows":2,"cols":1,"data":[1,2]}`), &m); e != nil || m.Get(1, 0) != 2 {
+		t.Fatal(e)
+	}
+}
+
+func TestEncodingEmptyMatrix(t *testing.T) {
+	if _, e := json.Marshal(&Matrix{}); !errors.Is(e, ErrInvalidArgument) {
+		t.Fatal(e)
+	}
+	if _, e := (Matrix{}).MarshalBinary(); !errors.Is(e, ErrInvalidArgument) {
+		t.Fatal(e)
+	}
+}
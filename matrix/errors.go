@@ -0,0 +1,81 @@
+/*	This file defines the errors of the matrix package
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errors, which can be matched with errors.Is
+var (
+	// ErrDimensionMismatch is matched by every DimensionError
+	ErrDimensionMismatch = errors.New("Error: dimension mismatch")
+	// ErrIndexOutOfRange is matched by every IndexError
+	ErrIndexOutOfRange = errors.New("Error: index out of range")
+	// ErrNotSquare is returned if an operation needs a square matrix
+	ErrNotSquare = errors.New("Error: matrix is not square")
+	// ErrInvalidArgument is returned for invalid scalar arguments
+	ErrInvalidArgument = errors.New("Error: invalid argument")
+	// ErrSingular is returned if a matrix is (numerically) singular
+	ErrSingular = errors.New("Error: matrix is singular")
+	// ErrNotPositiveDefinite is returned if a matrix is not positive definite
+	ErrNotPositiveDefinite = errors.New("Error: matrix is not positive definite")
+	// ErrNoConvergence is returned if an iterative method doesn't converge
+	ErrNoConvergence = errors.New("Error: iteration did not converge")
+	// ErrFormat is returned if encoded data can't be decoded
+	ErrFormat = errors.New("Error: invalid format")
+)
+
+// DimensionError describes operands with mismatching shapes.
+// Shapes are given as (rows, cols) for matrices and (size) for vectors.
+// B is nil, if the shape of a single operand A is invalid.
+type DimensionError struct {
+	Op string
+	A  []int
+	B  []int
+}
+
+// Error implements the error interface
+func (e *DimensionError) Error() string {
+	if e.B == nil {
+		return fmt.Sprintf("Error: dimension mismatch in %s: %v", e.Op, e.A)
+	}
+	return fmt.Sprintf("Error: dimension mismatch in %s: %v and %v", e.Op, e.A, e.B)
+}
+
+// Is reports whether target is ErrDimensionMismatch
+func (e *DimensionError) Is(target error) bool {
+	return target == ErrDimensionMismatch
+}
+
+// IndexError describes an index outside of the valid range.
+// Index holds the offending indices and Dims the dimensions
+// of the accessed matrix (rows, cols) or vector (size).
+type IndexError struct {
+	Op    string
+	Index []int
+	Dims  []int
+}
+
+// Error implements the error interface
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("Error: index %v out of range in %s: dimensions %v", e.Index, e.Op, e.Dims)
+}
+
+// Is reports whether target is ErrIndexOutOfRange
+func (e *IndexError) Is(target error) bool {
+	return target == ErrIndexOutOfRange
+}
+
+// shape returns the dimensions of a for error values
+func (a *Matrix) shape() []int {
+	return []int{a.rows, a.cols}
+}
+
+// shape returns the dimensions of a for error values
+func (a *Vector) shape() []int {
+	return []int{a.Size()}
+}
@@ -0,0 +1,103 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDimensionError(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	a := randMat(2, 3, rng)
+	b := randMat(3, 2, rng)
+	_, e := a.AddSafe(b)
+	var de *DimensionError
+	if !errors.Is(e, ErrDimensionMismatch) || !errors.As(e, &de) {
+		t.Fatal(e)
+	}
+	if de.Op != "Add" || de.A[0] != 2 || de.A[1] != 3 || de.B[0] != 3 || de.B[1] != 2 {
+		t.Fatal(de)
+	}
+	if a.Add(b) != nil {
+		t.Fatal("Add should return nil")
+	}
+	// single operand of invalid shape
+	wide, _ := ZeroMat(2, 3)
+	_, e = NewQR(wide)
+	if !errors.As(e, &de) || de.Op != "QR" || e.Error() != "Error: dimension mismatch in QR: [2 3]" {
+		t.Fatal(e)
+	}
+}
+
+func TestSafeErrors(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	a := randMat(2, 3, rng)
+	v := ZeroVec(3)
+	tests := []struct {
+		name string
+		e    error
+		want error
+	}{
+		{"GetSafe", second(a.GetSafe(5, 0)), ErrIndexOutOfRange},
+		{"SetRowSafe", a.SetRowSafe(0, ZeroVec(2)), ErrDimensionMismatch},
+		{"ScaleSafe", second(a.ScaleSafe(math.Inf(1))), ErrInvalidArgument},
+		{"MulSafe", second(a.MulSafe(a)), ErrDimensionMismatch},
+		{"NewLU", second(NewLU(a)), ErrNotSquare},
+		{"DotSafe", second(v.DotSafe(ZeroVec(2))), ErrDimensionMismatch},
+		{"Vector.SetSafe", v.SetSafe(3, 1), ErrIndexOutOfRange},
+		{"ZeroMat", second(ZeroMat(0, 1)), ErrInvalidArgument},
+		{"MatrixFromSlice", second(MatrixFromSlice([][]float64{{1}, {1, 2}})), ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.e, tt.want) {
+				t.Fatal(tt.e)
+			}
+		})
+	}
+	var ie *IndexError
+	if _, e := a.GetSafe(5, 0); !errors.As(e, &ie) || ie.Index[0] != 5 {
+		t.Fatal(e)
+	}
+}
+
+// second returns the error of a two-valued call
+func second[T any](_ T, e error) error {
+	return e
+}
+
+func TestEmptyVector(t *testing.T) {
+	var v Vector
+	if i, m := v.Min(); i != -1 || !math.IsNaN(m) {
+		t.Fatal("Min", i, m)
+	}
+	if i, m := v.Max(); i != -1 || !math.IsNaN(m) {
+		t.Fatal("Max", i, m)
+	}
+	if _, _, e := v.MinSafe(); !errors.Is(e, ErrInvalidArgument) {
+		t.Fatal(e)
+	}
+	if _, _, e := v.MaxSafe(); !errors.Is(e, ErrInvalidArgument) {
+		t.Fatal(e)
+	}
+	if m, e := v.MeanSafe(); !math.IsNaN(m) || !errors.Is(e, ErrInvalidArgument) {
+		t.Fatal(m, e)
+	}
+	if m, e := v.VarSafe(); !math.IsNaN(m) || !errors.Is(e, ErrInvalidArgument) {
+		t.Fatal(m, e)
+	}
+	w := VecFromSlice([]float64{3, -1, 5, 1})
+	if i, m, e := w.MinSafe(); i != 1 || m != -1 || e != nil {
+		t.Fatal(i, m, e)
+	}
+	if i, m, e := w.MaxSafe(); i != 2 || m != 5 || e != nil {
+		t.Fatal(i, m, e)
+	}
+	if m, e := w.MeanSafe(); m != 2 || e != nil {
+		t.Fatal(m, e)
+	}
+	if m, e := w.VarSafe(); math.Abs(m-20.0/3) > 1e-14 || e != nil {
+		t.Fatal(m, e)
+	}
+}
@@ -0,0 +1,202 @@
+/*	This file implements renderers of matrices and vectors for reports and
+	logs: LaTeX, Markdown tables and compact single-line output
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// default fmt format of the entries
+const defaultEntryFormat = "%.4g"
+
+// ellipsis marks elided rows and columns
+const ellipsis = "…"
+
+// LaTeX renders m as LaTeX bmatrix environment.
+// Elided rows and columns are marked with \vdots, \cdots and \ddots.
+// opt may be nil for the defaults.
+func (m *Matrix) LaTeX(opt *FormatOptions) string {
+	cells, rows, cols := m.cells(opt)
+	var sb strings.Builder
+	sb.WriteString("\\begin{bmatrix}\n")
+	for k, row := range cells {
+		for l, cell := range row {
+			if l > 0 {
+				sb.WriteString(" & ")
+			}
+			// vertical, horizontal or diagonal dots
+			switch {
+			case rows[k] < 0 && cols[l] < 0:
+				cell = "\\ddots"
+			case rows[k] < 0:
+				cell = "\\vdots"
+			case cols[l] < 0:
+				cell = "\\cdots"
+			}
+			sb.WriteString(cell)
+		}
+		if k < len(cells)-1 {
+			sb.WriteString(" \\\\")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\\end{bmatrix}")
+	return sb.String()
+}
+
+// Markdown renders m as Markdown table with right-aligned columns.
+// The header holds opt.Names or the column indices.
+// opt may be nil for the defaults.
+func (m *Matrix) Markdown(opt *FormatOptions) string {
+	cells, _, cols := m.cells(opt)
+	var sb strings.Builder
+	// header
+	sb.WriteString("|")
+	for _, j := range cols {
+		name := ellipsis
+		if j >= 0 && opt != nil && j < len(opt.Names) {
+			name = opt.Names[j]
+		} else if j >= 0 {
+			name = strconv.Itoa(j)
+		}
+		sb.WriteString(" " + name + " |")
+	}
+	sb.WriteString("\n|")
+	for range cols {
+		sb.WriteString("---:|")
+	}
+	// entries
+	for _, row := range cells {
+		sb.WriteString("\n|")
+		for _, cell := range row {
+			sb.WriteString(" " + cell + " |")
+		}
+	}
+	return sb.String()
+}
+
+// Compact renders m on a single line, e.g. [[1 2] [3 4]].
+// opt may be nil for the defaults.
+func (m *Matrix) Compact(opt *FormatOptions) string {
+	cells, rows, _ := m.cells(opt)
+	s := make([]string, len(cells))
+	for k, row := range cells {
+		if rows[k] < 0 {
+			s[k] = ellipsis
+			continue
+		}
+		s[k] = "[" + strings.Join(row, " ") + "]"
+	}
+	return "[" + strings.Join(s, " ") + "]"
+}
+
+// grid renders m as aligned grid with right-aligned columns,
+// one row per line
+func (m *Matrix) grid(format string) string {
+	cells, _, cols := m.cells(&FormatOptions{Format: format})
+	// compute column widths
+	widths := make([]int, len(cols))
+	for _, row := range cells {
+		for l, cell := range row {
+			widths[l] = max(widths[l], utf8.RuneCountInString(cell))
+		}
+	}
+	lines := make([]string, len(cells))
+	for k, row := range cells {
+		for l, cell := range row {
+			row[l] = strings.Repeat(" ", widths[l]-utf8.RuneCountInString(cell)) + cell
+		}
+		lines[k] = strings.Join(row, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LaTeX renders v as column vector in a LaTeX bmatrix environment.
+// opt.MaxRows elides elements. opt may be nil for the defaults.
+func (v *Vector) LaTeX(opt *FormatOptions) string {
+	return v.column().LaTeX(opt)
+}
+
+// Markdown renders v as Markdown table with a single column.
+// opt.MaxRows elides elements. opt may be nil for the defaults.
+func (v *Vector) Markdown(opt *FormatOptions) string {
+	return v.column().Markdown(opt)
+}
+
+// Compact renders v on a single line, e.g. [1 2 3].
+// opt.MaxCols elides elements. opt may be nil for the defaults.
+func (v *Vector) Compact(opt *FormatOptions) string {
+	if v.Size() == 0 {
+		return "[]"
+	}
+	m, _ := ZeroMat(1, v.Size())
+	m.SetRow(0, v)
+	cells, _, _ := m.cells(opt)
+	return "[" + strings.Join(cells[0], " ") + "]"
+}
+
+// column returns v as n x 1 matrix and an empty vector as 0 x 0 matrix,
+// which ZeroMat doesn't create
+func (v *Vector) column() (m *Matrix) {
+	if v.Size() == 0 {
+		m = new(Matrix)
+		return
+	}
+	m = v.Mat()
+	return
+}
+
+// cells returns the formatted entries of m and the indices of the shown
+// rows and columns, where elided rows and columns are replaced by a single
+// row or column of ellipses with index -1
+func (m *Matrix) cells(opt *FormatOptions) (cells [][]string, rows, cols []int) {
+	format := defaultEntryFormat
+	var maxRows, maxCols int
+	if opt != nil {
+		if opt.Format != "" {
+			format = opt.Format
+		}
+		maxRows, maxCols = opt.MaxRows, opt.MaxCols
+	}
+	rows = elided(m.rows, maxRows)
+	cols = elided(m.cols, maxCols)
+	cells = make([][]string, len(rows))
+	for k, i := range rows {
+		cells[k] = make([]string, len(cols))
+		for l, j := range cols {
+			if i < 0 || j < 0 {
+				cells[k][l] = ellipsis
+			} else {
+				cells[k][l] = fmt.Sprintf(format, m.getEntry(i, j))
+			}
+		}
+	}
+	return
+}
+
+// elided returns the indices of the shown entries out of n, where -1 marks
+// the position of the elided ones. limit <= 0 shows all entries.
+func elided(n, limit int) (idx []int) {
+	if limit <= 0 || n <= limit {
+		for i := 0; i < n; i++ {
+			idx = append(idx, i)
+		}
+		return
+	}
+	// show the first and last entries
+	head := (limit + 1) / 2
+	for i := 0; i < head; i++ {
+		idx = append(idx, i)
+	}
+	idx = append(idx, -1)
+	for i := n - (limit - head); i < n; i++ {
+		idx = append(idx, i)
+	}
+	return
+}
@@ -0,0 +1,77 @@
+package matrix
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormatVerbs(t *testing.T) {
+	m, _ := MatrixFromSlice([][]float64{{1, -2.5, 3}, {40, 5, 6.125}})
+	v := VecFromSlice([]float64{1, 2, 3})
+	tests := []struct {
+		name, got, want string
+	}{
+		{"%v", fmt.Sprintf("%v", m), " 1 -2.5     3\n40    5 6.125"},
+		{"value", fmt.Sprint(*m), " 1 -2.5     3\n40    5 6.125"},
+		{"%.2f", fmt.Sprintf("%.2f", m), " 1.00 -2.50 3.00\n40.00  5.00 6.12"},
+		{"%+.0f", fmt.Sprintf("%+.0f", m), " +1 -2 +3\n+40 +5 +6"},
+		{"%s", fmt.Sprintf("%s", m), m.String()},
+		{"%d", fmt.Sprintf("%d", m), "%!d(matrix.Matrix=2x3)"},
+		{"vector %v", fmt.Sprint(v), "[1 2 3]"},
+		{"vector %.1f", fmt.Sprintf("%.1f", v), "[1.0 2.0 3.0]"},
+		{"vector %s", fmt.Sprintf("%s", v), v.String()},
+		{"empty vector", fmt.Sprintf("%.2f", Vector{}), "[]"},
+		{"empty matrix", fmt.Sprintf("%.2f", Matrix{}), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Fatalf("%q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompact(t *testing.T) {
+	m, _ := MatrixFromSlice([][]float64{{1, -2.5, 3}, {40, 5, 6.125}})
+	big, _ := ZeroMat(10, 10)
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			big.Set(i, j, float64(10*i+j))
+		}
+	}
+	elide := &FormatOptions{MaxRows: 4, MaxCols: 3, Format: "%.0f"}
+	tests := []struct {
+		name, got, want string
+	}{
+		{"matrix", m.Compact(nil), "[[1 -2.5 3] [40 5 6.125]]"},
+		{"elided", big.Compact(elide), "[[0 1 … 9] [10 11 … 19] … [80 81 … 89] [90 91 … 99]]"},
+		{"vector", VecFromSlice([]float64{1, 2, 3}).Compact(nil), "[1 2 3]"},
+		{"empty vector", (&Vector{}).Compact(nil), "[]"},
+		{"empty matrix", (&Matrix{}).Compact(nil), "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Fatalf("%q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLaTeXMarkdown(t *testing.T) {
+	m, _ := MatrixFromSlice([][]float64{{1, -2.5}, {40, 5}})
+	l := m.LaTeX(nil)
+	if !strings.HasPrefix(l, `\begin{bmatrix}`) || !strings.Contains(l, `1 & -2.5 \\`) || !strings.HasSuffix(strings.TrimSpace(l), `\end{bmatrix}`) {
+		t.Fatal(l)
+	}
+	md := m.Markdown(&FormatOptions{Names: []string{"a", "b"}})
+	lines := strings.Split(strings.TrimSpace(md), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[0], "a") || !strings.Contains(lines[0], "b") || !strings.Contains(lines[3], "40") {
+		t.Fatal(md)
+	}
+	// renderers must not panic for empty values
+	_ = (&Matrix{}).LaTeX(nil) + (&Matrix{}).Markdown(nil)
+	_ = (&Vector{}).LaTeX(nil) + (&Vector{}).Markdown(&FormatOptions{MaxRows: 2})
+}
@@ -0,0 +1,523 @@
+/*	This file implements the generic matrix and vector types GenMatrix and
+	GenVector for element types like float32, int or complex128, and the
+	conversions between element types. Matrix and Vector are defined as
+	GenMatrix[float64] and GenVector[float64] with the full float64 API.
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"fmt"
+)
+
+// ZeroGenMat creates a zero matrix with r rows and c columns
+func ZeroGenMat[T Number](r, c int) (m *GenMatrix[T], e error) {
+	if r <= 0 || c <= 0 {
+		e = fmt.Errorf("%w: invalid dimensions %dx%d", ErrInvalidArgument, r, c)
+		return
+	}
+	m = &GenMatrix[T]{rows: r, cols: c, rstride: c, cstride: 1, entries: make([]T, r*c)}
+	return
+}
+
+// IdGenMat creates a identity matrix with r rows and c columns
+func IdGenMat[T Number](r, c int) (m *GenMatrix[T], e error) {
+	m, e = ZeroGenMat[T](r, c)
+	if e != nil {
+		return
+	}
+	for i := 0; i < min(r, c); i++ {
+		m.entries[i*c+i] = 1
+	}
+	return
+}
+
+// GenMatFromSlice creates a matrix from a 2d slice.
+// Returns an error, if the slice is empty or the rows have different lengths
+func GenMatFromSlice[T Number](slice [][]T) (m *GenMatrix[T], e error) {
+	// check size of slice
+	if len(slice) < 1 || len(slice[0]) < 1 {
+		e = fmt.Errorf("%w: empty slice", ErrInvalidArgument)
+		return
+	}
+	m, _ = ZeroGenMat[T](len(slice), len(slice[0]))
+	for i, row := range slice {
+		if len(row) != m.cols {
+			m = nil
+			e = fmt.Errorf("%w: rows of different length", ErrInvalidArgument)
+			return
+		}
+		copy(m.entries[i*m.cols:], row)
+	}
+	return
+}
+
+// ZeroGenVec creates a zero vector of size n.
+// Returns nil, if n < 1 (like ZeroVec)
+func ZeroGenVec[T Number](n int) (v *GenVector[T]) {
+	if n < 1 {
+		return
+	}
+	v = &GenVector[T]{entries: make([]T, n), inc: 1}
+	return
+}
+
+// GenVecFromSlice creates a vector with a copy of the slice s.
+// Returns nil, if s is empty (like VecFromSlice)
+func GenVecFromSlice[T Number](s []T) (v *GenVector[T]) {
+	if len(s) < 1 {
+		return
+	}
+	v = &GenVector[T]{entries: append([]T(nil), s...), inc: 1}
+	return
+}
+
+// ToGenMat converts a to a generic matrix with element type T.
+// The entries are copied, conversions to integer types truncate.
+// A *Matrix converts to *GenMatrix[float64] without copying,
+// i.e. (*GenMatrix[float64])(a) shares the entries of a.
+func ToGenMat[T Real](a *Matrix) (m *GenMatrix[T]) {
+	m, _ = ZeroGenMat[T](a.rows, a.cols)
+	for i := 0; i < a.rows; i++ {
+		for j := 0; j < a.cols; j++ {
+			m.entries[i*a.cols+j] = T(a.getEntry(i, j))
+		}
+	}
+	return
+}
+
+// FromGenMat converts the generic matrix a to a float64 Matrix.
+// The entries are copied, (*Matrix)(a) converts a *GenMatrix[float64]
+// without copying.
+func FromGenMat[T Real](a *GenMatrix[T]) (m *Matrix) {
+	m, _ = ZeroMat(a.rows, a.cols)
+	for i := 0; i < a.rows; i++ {
+		for j := 0; j < a.cols; j++ {
+			m.entries[i*a.cols+j] = float64(a.getEntry(i, j))
+		}
+	}
+	return
+}
+
+// ToGenVec converts a to a generic vector with element type T.
+// The elements are copied, conversions to integer types truncate.
+// A *Vector converts to *GenVector[float64] without copying.
+func ToGenVec[T Real](a *Vector) (v *GenVector[T]) {
+	v = ZeroGenVec[T](a.Size())
+	if v == nil {
+		return
+	}
+	for i := range v.entries {
+		v.entries[i] = T(a.at(i))
+	}
+	return
+}
+
+// FromGenVec converts the generic vector a to a float64 Vector.
+// The elements are copied, (*Vector)(a) converts a *GenVector[float64]
+// without copying.
+func FromGenVec[T Real](a *GenVector[T]) (v *Vector) {
+	v = ZeroVec(a.Size())
+	if v == nil {
+		return
+	}
+	for i := range v.entries {
+		v.entries[i] = float64(a.at(i))
+	}
+	return
+}
+
+// Rows returns the number of rows of the matrix
+func (m *GenMatrix[T]) Rows() int {
+	return m.rows
+}
+
+// Cols returns the number of cols of the matrix
+func (m *GenMatrix[T]) Cols() int {
+	return m.cols
+}
+
+// Dims returns the number of rows and cols of the matrix
+func (m *GenMatrix[T]) Dims() (r, c int) {
+	return m.rows, m.cols
+}
+
+// Get(i,j) returns the element in row i and column j.
+// returns NaN if invalid index, zero for integer types without NaN
+func (m *GenMatrix[T]) Get(i, j int) T {
+	v, e := m.GetSafe(i, j)
+	if e != nil {
+		return nan[T]()
+	}
+	return v
+}
+
+// GetSafe(i,j) returns the element in row i and column j and an error value.
+// Returns an IndexError, if invalid indices
+func (m *GenMatrix[T]) GetSafe(i, j int) (elem T, e error) {
+	// check indices
+	if i < 0 || j < 0 || i >= m.rows || j >= m.cols {
+		e = &IndexError{Op: "Get", Index: []int{i, j}, Dims: []int{m.rows, m.cols}}
+		return
+	}
+	elem = m.getEntry(i, j)
+	return
+}
+
+// Set(i,j, v) sets the entry of row i and col j to value v.
+// No update, if invalid indices
+func (m *GenMatrix[T]) Set(i, j int, v T) {
+	m.SetSafe(i, j, v)
+}
+
+// SetSafe sets the entry of row i and col j to value v.
+// Returns an IndexError, if invalid indices
+func (m *GenMatrix[T]) SetSafe(i, j int, v T) (e error) {
+	// check indices
+	if i < 0 || j < 0 || i >= m.rows || j >= m.cols {
+		e = &IndexError{Op: "Set", Index: []int{i, j}, Dims: []int{m.rows, m.cols}}
+		return
+	}
+	m.setEntry(i, j, v)
+	return
+}
+
+// CopyMat returns a copy of the matrix
+func (a *GenMatrix[T]) CopyMat() (m *GenMatrix[T]) {
+	m, _ = ZeroGenMat[T](a.rows, a.cols)
+	for i := 0; i < a.rows; i++ {
+		for j := 0; j < a.cols; j++ {
+			m.entries[i*a.cols+j] = a.getEntry(i, j)
+		}
+	}
+	return
+}
+
+// T returns a transposed copy of the matrix
+func (a *GenMatrix[T]) T() (m *GenMatrix[T]) {
+	m, _ = ZeroGenMat[T](a.cols, a.rows)
+	for i := 0; i < a.rows; i++ {
+		for j := 0; j < a.cols; j++ {
+			m.entries[j*a.rows+i] = a.getEntry(i, j)
+		}
+	}
+	return
+}
+
+// Add adds two matrices: c = a + b.
+// Dimension mismatch returns nil.
+func (a *GenMatrix[T]) Add(b *GenMatrix[T]) (c *GenMatrix[T]) {
+	c, _ = a.AddSafe(b)
+	return
+}
+
+// AddSafe adds two matrices: c = a + b.
+// Returns a DimensionError, if dimensions mismatch.
+func (a *GenMatrix[T]) AddSafe(b *GenMatrix[T]) (c *GenMatrix[T], e error) {
+	c, e = a.zip("Add", b, func(x, y T) T { return x + y })
+	return
+}
+
+// Sub subtracts two matrices: c = a - b.
+// Dimension mismatch returns nil.
+func (a *GenMatrix[T]) Sub(b *GenMatrix[T]) (c *GenMatrix[T]) {
+	c, _ = a.SubSafe(b)
+	return
+}
+
+// SubSafe subtracts two matrices: c = a - b.
+// Returns a DimensionError, if dimensions mismatch.
+func (a *GenMatrix[T]) SubSafe(b *GenMatrix[T]) (c *GenMatrix[T], e error) {
+	c, e = a.zip("Sub", b, func(x, y T) T { return x - y })
+	return
+}
+
+// CWiseProd computes the component-wise product of two matrices.
+// Dimension mismatch returns nil.
+func (a *GenMatrix[T]) CWiseProd(b *GenMatrix[T]) (c *GenMatrix[T]) {
+	c, _ = a.CWiseProdSafe(b)
+	return
+}
+
+// CWiseProdSafe computes the component-wise product of two matrices.
+// Returns a DimensionError, if dimensions mismatch.
+func (a *GenMatrix[T]) CWiseProdSafe(b *GenMatrix[T]) (c *GenMatrix[T], e error) {
+	c, e = a.zip("CWiseProd", b, func(x, y T) T { return x * y })
+	return
+}
+
+// Scale multiplies every entry with factor
+func (a *GenMatrix[T]) Scale(factor T) (m *GenMatrix[T]) {
+	m = a.ApplyFunc(func(x T) T { return factor * x })
+	return
+}
+
+// ApplyFunc applies the function f to every entry and returns the result
+func (a *GenMatrix[T]) ApplyFunc(f func(T) T) (m *GenMatrix[T]) {
+	m = a.CopyMat()
+	for k, x := range m.entries {
+		m.entries[k] = f(x)
+	}
+	return
+}
+
+// Mul computes the matrix product c = a * b.
+// Dimension mismatch (a.Cols() != b.Rows()) returns nil.
+func (a *GenMatrix[T]) Mul(b *GenMatrix[T]) (c *GenMatrix[T]) {
+	c, _ = a.MulSafe(b)
+	return
+}
+
+// MulSafe computes the matrix product c = a * b.
+// Returns a DimensionError, if a.Cols() != b.Rows().
+func (a *GenMatrix[T]) MulSafe(b *GenMatrix[T]) (c *GenMatrix[T], e error) {
+	// check if dimensions match
+	if a.cols != b.rows {
+		e = &DimensionError{Op: "Mul", A: []int{a.rows, a.cols}, B: []int{b.rows, b.cols}}
+		return
+	}
+	c, _ = ZeroGenMat[T](a.rows, b.cols)
+	// i-k-j loop order runs over rows of b and c
+	for i := 0; i < a.rows; i++ {
+		cRow := c.entries[i*b.cols : (i+1)*b.cols]
+		for k := 0; k < a.cols; k++ {
+			aik := a.getEntry(i, k)
+			for j := range cRow {
+				cRow[j] += aik * b.getEntry(k, j)
+			}
+		}
+	}
+	return
+}
+
+// MulVec computes the matrix-vector product w = a * v.
+// Dimension mismatch (a.Cols() != v.Size()) returns nil.
+func (a *GenMatrix[T]) MulVec(v *GenVector[T]) (w *GenVector[T]) {
+	w, _ = a.MulVecSafe(v)
+	return
+}
+
+// MulVecSafe computes the matrix-vector product w = a * v.
+// Returns a DimensionError, if a.Cols() != v.Size().
+func (a *GenMatrix[T]) MulVecSafe(v *GenVector[T]) (w *GenVector[T], e error) {
+	// check if dimensions match
+	if a.cols != v.Size() {
+		e = &DimensionError{Op: "MulVec", A: []int{a.rows, a.cols}, B: []int{v.Size()}}
+		return
+	}
+	w = ZeroGenVec[T](a.rows)
+	for i := range w.entries {
+		var sum T
+		for j := 0; j < a.cols; j++ {
+			sum += a.getEntry(i, j) * v.at(j)
+		}
+		w.entries[i] = sum
+	}
+	return
+}
+
+// zip applies f component-wise to the entries of a and b
+func (a *GenMatrix[T]) zip(op string, b *GenMatrix[T], f func(x, y T) T) (c *GenMatrix[T], e error) {
+	// check if dimensions match
+	if a.rows != b.rows || a.cols != b.cols {
+		e = &DimensionError{Op: op, A: []int{a.rows, a.cols}, B: []int{b.rows, b.cols}}
+		return
+	}
+	c, _ = ZeroGenMat[T](a.rows, a.cols)
+	for i := 0; i < a.rows; i++ {
+		for j := 0; j < a.cols; j++ {
+			c.entries[i*a.cols+j] = f(a.getEntry(i, j), b.getEntry(i, j))
+		}
+	}
+	return
+}
+
+// getEntry returns the entry (i,j) without checking the indices
+func (a *GenMatrix[T]) getEntry(i, j int) T {
+	return a.entries[a.rstride*i+a.cstride*j]
+}
+
+// setEntry sets the entry (i,j) to v without checking the indices
+func (a *GenMatrix[T]) setEntry(i, j int, v T) {
+	a.entries[a.rstride*i+a.cstride*j] = v
+}
+
+// Size returns the number of elements of the vector
+func (a *GenVector[T]) Size() int {
+	if len(a.entries) == 0 {
+		return 0
+	}
+	// entries of a view end with the last element
+	return (len(a.entries)-1)/a.inc + 1
+}
+
+// Get(i) returns the i-th element.
+// returns NaN if invalid index, zero for integer types without NaN
+func (v *GenVector[T]) Get(i int) T {
+	elem, e := v.GetSafe(i)
+	if e != nil {
+		return nan[T]()
+	}
+	return elem
+}
+
+// GetSafe(i) returns the i-th element and an error value.
+// Returns an IndexError, if invalid index
+func (v *GenVector[T]) GetSafe(i int) (elem T, e error) {
+	// check index
+	if i < 0 || i >= v.Size() {
+		e = &IndexError{Op: "Get", Index: []int{i}, Dims: []int{v.Size()}}
+		return
+	}
+	elem = v.at(i)
+	return
+}
+
+// Set(i, value) sets the i-th element to value.
+// No update, if invalid index
+func (v *GenVector[T]) Set(i int, value T) {
+	v.SetSafe(i, value)
+}
+
+// SetSafe sets the i-th element to value.
+// Returns an IndexError, if invalid index
+func (v *GenVector[T]) SetSafe(i int, value T) (e error) {
+	// check index
+	if i < 0 || i >= v.Size() {
+		e = &IndexError{Op: "Set", Index: []int{i}, Dims: []int{v.Size()}}
+		return
+	}
+	v.entries[i*v.inc] = value
+	return
+}
+
+// CopyVec returns a copy of the vector
+func (a *GenVector[T]) CopyVec() (v *GenVector[T]) {
+	v = GenVecFromSlice(a.Slice())
+	return
+}
+
+// Slice returns a copy of the elements as slice
+func (a *GenVector[T]) Slice() (s []T) {
+	s = make([]T, a.Size())
+	for i := range s {
+		s[i] = a.at(i)
+	}
+	return
+}
+
+// Add adds two vectors: c = a + b.
+// Dimension mismatch returns nil.
+func (a *GenVector[T]) Add(b *GenVector[T]) (c *GenVector[T]) {
+	c, _ = a.AddSafe(b)
+	return
+}
+
+// AddSafe adds two vectors: c = a + b.
+// Returns a DimensionError, if sizes mismatch.
+func (a *GenVector[T]) AddSafe(b *GenVector[T]) (c *GenVector[T], e error) {
+	c, e = a.zip("Add", b, func(x, y T) T { return x + y })
+	return
+}
+
+// Sub subtracts two vectors: c = a - b.
+// Dimension mismatch returns nil.
+func (a *GenVector[T]) Sub(b *GenVector[T]) (c *GenVector[T]) {
+	c, _ = a.SubSafe(b)
+	return
+}
+
+// SubSafe subtracts two vectors: c = a - b.
+// Returns a DimensionError, if sizes mismatch.
+func (a *GenVector[T]) SubSafe(b *GenVector[T]) (c *GenVector[T], e error) {
+	c, e = a.zip("Sub", b, func(x, y T) T { return x - y })
+	return
+}
+
+// CWiseProd computes the component-wise product of two vectors.
+// Dimension mismatch returns nil.
+func (a *GenVector[T]) CWiseProd(b *GenVector[T]) (c *GenVector[T]) {
+	c, _ = a.CWiseProdSafe(b)
+	return
+}
+
+// CWiseProdSafe computes the component-wise product of two vectors.
+// Returns a DimensionError, if sizes mismatch.
+func (a *GenVector[T]) CWiseProdSafe(b *GenVector[T]) (c *GenVector[T], e error) {
+	c, e = a.zip("CWiseProd", b, func(x, y T) T { return x * y })
+	return
+}
+
+// Scale multiplies every element with factor
+func (a *GenVector[T]) Scale(factor T) (v *GenVector[T]) {
+	v = a.ApplyFunc(func(x T) T { return factor * x })
+	return
+}
+
+// ApplyFunc applies the function f to every element and returns the result
+func (a *GenVector[T]) ApplyFunc(f func(T) T) (v *GenVector[T]) {
+	v = a.CopyVec()
+	for i, x := range v.entries {
+		v.entries[i] = f(x)
+	}
+	return
+}
+
+// Dot computes the dot product sum a_i * b_i.
+// Complex elements are not conjugated.
+// Dimension mismatch returns zero.
+func (a *GenVector[T]) Dot(b *GenVector[T]) T {
+	result, _ := a.DotSafe(b)
+	return result
+}
+
+// DotSafe computes the dot product sum a_i * b_i.
+// Returns a DimensionError, if sizes mismatch.
+func (a *GenVector[T]) DotSafe(b *GenVector[T]) (result T, e error) {
+	// check sizes
+	if a.Size() != b.Size() {
+		e = &DimensionError{Op: "Dot", A: []int{a.Size()}, B: []int{b.Size()}}
+		return
+	}
+	for i := 0; i < a.Size(); i++ {
+		result += a.at(i) * b.at(i)
+	}
+	return
+}
+
+// Sum returns the sum of all elements
+func (a *GenVector[T]) Sum() (sum T) {
+	for i := 0; i < a.Size(); i++ {
+		sum += a.at(i)
+	}
+	return
+}
+
+// zip applies f component-wise to the elements of a and b
+func (a *GenVector[T]) zip(op string, b *GenVector[T], f func(x, y T) T) (c *GenVector[T], e error) {
+	// check sizes
+	if a.Size() != b.Size() {
+		e = &DimensionError{Op: op, A: []int{a.Size()}, B: []int{b.Size()}}
+		return
+	}
+	c = ZeroGenVec[T](a.Size())
+	for i := range c.entries {
+		c.entries[i] = f(a.at(i), b.at(i))
+	}
+	return
+}
+
+// at returns the i-th element without checking the index
+func (a *GenVector[T]) at(i int) T {
+	return a.entries[i*a.inc]
+}
+
+// nan returns NaN for floating point and complex types
+// and zero for integer types, which have no NaN
+func nan[T Number]() (x T) {
+	if T(1)/2 == 0 {
+		return
+	}
+	return x / x
+}
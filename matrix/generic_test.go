@@ -0,0 +1,123 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestGenMatrixInt(t *testing.T) {
+	a, _ := GenMatFromSlice([][]int{{1, 2}, {3, 4}})
+	id, _ := IdGenMat[int](2, 2)
+	tests := []struct {
+		name      string
+		got, want int
+	}{
+		{"Mul", a.Mul(id).Get(1, 0), 3},
+		{"Add", a.Add(a).Get(1, 1), 8},
+		{"T", a.T().Get(0, 1), 3},
+		{"Scale", a.Scale(3).Get(0, 1), 6},
+		{"MulVec", a.MulVec(GenVecFromSlice([]int{1, 1})).Get(1), 7},
+		{"out of range", a.Get(5, 5), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Fatal(tt.got)
+			}
+		})
+	}
+	if _, e := a.GetSafe(2, 0); !errors.Is(e, ErrIndexOutOfRange) {
+		t.Fatal(e)
+	}
+	if a.Mul(ToGenMat[int](randMat(3, 3, rand.New(rand.NewSource(15))))) != nil {
+		t.Fatal("mismatched product should be nil")
+	}
+}
+
+func TestGenMatrixFloat32(t *testing.T) {
+	rng := rand.New(rand.NewSource(15))
+	a := randMat(3, 4, rng)
+	b := randMat(4, 2, rng)
+	fa := ToGenMat[float32](a)
+	fb := ToGenMat[float32](b)
+	matClose(t, "Mul", FromGenMat(fa.Mul(fb)), a.Mul(b), 1e-5)
+	v := randVec(5, rng)
+	vecClose(t, "ToGenVec", FromGenVec(ToGenVec[float64](v)), v, 0)
+}
+
+func TestGenComplex(t *testing.T) {
+	a, _ := GenMatFromSlice([][]complex128{{1i, 0}, {0, 1}})
+	if a.Mul(a).Get(0, 0) != -1 || a.Scale(2i).Get(1, 1) != 2i {
+		t.Fatal("complex matrix")
+	}
+	v := GenVecFromSlice([]complex128{1i, 2})
+	if v.Dot(v) != 3 || v.Sum() != 2+1i {
+		t.Fatal("complex vector")
+	}
+}
+
+func TestGenNaN(t *testing.T) {
+	type myFloat float64
+	gf, _ := ZeroGenMat[float32](1, 1)
+	gm, _ := ZeroGenMat[myFloat](1, 1)
+	gv := ZeroGenVec[float64](2)
+	tests := []struct {
+		name string
+		x    float64
+	}{
+		{"float32", float64(gf.Get(1, 1))},
+		{"named float", float64(gm.Get(1, 1))},
+		{"vector", gv.Get(9)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !math.IsNaN(tt.x) {
+				t.Fatal(tt.x)
+			}
+		})
+	}
+}
+
+func TestGenValidation(t *testing.T) {
+	if ZeroGenVec[float32](-1) != nil || ZeroGenVec[int](0) != nil || GenVecFromSlice([]int{}) != nil {
+		t.Fatal("empty vectors should be nil")
+	}
+	if ToGenVec[float32](&Vector{}) != nil {
+		t.Fatal("ToGenVec of empty vector should be nil")
+	}
+	if _, e := GenMatFromSlice([][]int{{1}, {1, 2}}); !errors.Is(e, ErrInvalidArgument) {
+		t.Fatal(e)
+	}
+	if _, e := GenMatFromSlice([][]int{}); !errors.Is(e, ErrInvalidArgument) {
+		t.Fatal(e)
+	}
+}
+
+func TestGenFloat64Instantiation(t *testing.T) {
+	a, _ := MatrixFromSlice([][]float64{{1, 2, 3}, {4, 5, 6}})
+	g := (*GenMatrix[float64])(a)
+	g.Set(0, 0, 7)
+	if a.Get(0, 0) != 7 {
+		t.Fatal("storage not shared")
+	}
+	// strided views work with the generic methods
+	tv := (*GenMatrix[float64])(a.T())
+	if tv.Get(2, 1) != 6 || tv.Rows() != 3 {
+		t.Fatal(tv.Get(2, 1))
+	}
+	matClose(t, "Mul", (*Matrix)(tv.Mul(g)), a.TMul(a), 1e-12)
+	matClose(t, "CopyMat", (*Matrix)(tv.CopyMat()), a.T(), 0)
+	matClose(t, "Add", (*Matrix)(tv.Add(tv)), a.T().Scale(2), 0)
+	matClose(t, "FromGenMat", FromGenMat(tv), naiveT(a), 0)
+	col := (*GenVector[float64])(a.ColView(1))
+	if col.Size() != 2 || col.Get(1) != 5 || col.Sum() != 7 {
+		t.Fatal(col.Slice())
+	}
+	vecClose(t, "MulVec", (*Vector)(tv.MulVec(col)), a.T().MulVec(a.ColView(1)), 1e-12)
+	vecClose(t, "FromGenVec", FromGenVec(col), a.GetCol(1), 0)
+	if !math.IsNaN(g.Get(5, 5)) {
+		t.Fatal("Get out of range")
+	}
+}
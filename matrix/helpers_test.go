@@ -0,0 +1,87 @@
+package matrix
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// randMat returns an r x c matrix with standard normal entries
+func randMat(r, c int, rng *rand.Rand) *Matrix {
+	m, _ := ZeroMat(r, c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.Set(i, j, rng.NormFloat64())
+		}
+	}
+	return m
+}
+
+// randVec returns a vector of size n with standard normal elements
+func randVec(n int, rng *rand.Rand) *Vector {
+	v := ZeroVec(n)
+	for i := 0; i < n; i++ {
+		v.Set(i, rng.NormFloat64())
+	}
+	return v
+}
+
+// naiveMul returns a * b computed with the textbook triple loop
+func naiveMul(a, b *Matrix) *Matrix {
+	c, _ := ZeroMat(a.Rows(), b.Cols())
+	for i := 0; i < a.Rows(); i++ {
+		for j := 0; j < b.Cols(); j++ {
+			s := 0.0
+			for k := 0; k < a.Cols(); k++ {
+				s += a.Get(i, k) * b.Get(k, j)
+			}
+			c.Set(i, j, s)
+		}
+	}
+	return c
+}
+
+// naiveT returns a copy of the transpose of a
+func naiveT(a *Matrix) *Matrix {
+	c, _ := ZeroMat(a.Cols(), a.Rows())
+	for i := 0; i < a.Rows(); i++ {
+		for j := 0; j < a.Cols(); j++ {
+			c.Set(j, i, a.Get(i, j))
+		}
+	}
+	return c
+}
+
+// matClose fails, if a and b differ by more than tol in an entry
+func matClose(t *testing.T, name string, a, b *Matrix, tol float64) {
+	t.Helper()
+	if a == nil || b == nil {
+		t.Fatalf("%s: nil matrix", name)
+	}
+	if a.Rows() != b.Rows() || a.Cols() != b.Cols() {
+		t.Fatalf("%s: dims %dx%d vs %dx%d", name, a.Rows(), a.Cols(), b.Rows(), b.Cols())
+	}
+	for i := 0; i < a.Rows(); i++ {
+		for j := 0; j < a.Cols(); j++ {
+			if math.Abs(a.Get(i, j)-b.Get(i, j)) > tol {
+				t.Fatalf("%s: (%d,%d) %v vs %v", name, i, j, a.Get(i, j), b.Get(i, j))
+			}
+		}
+	}
+}
+
+// vecClose fails, if a and b differ by more than tol in an element
+func vecClose(t *testing.T, name string, a, b *Vector, tol float64) {
+	t.Helper()
+	if a == nil || b == nil {
+		t.Fatalf("%s: nil vector", name)
+	}
+	if a.Size() != b.Size() {
+		t.Fatalf("%s: size %d vs %d", name, a.Size(), b.Size())
+	}
+	for i := 0; i < a.Size(); i++ {
+		if math.Abs(a.Get(i)-b.Get(i)) > tol {
+			t.Fatalf("%s: [%d] %v vs %v", name, i, a.Get(i), b.Get(i))
+		}
+	}
+}
@@ -0,0 +1,556 @@
+/*	This file implements in-place and destination-receiving operations,
+	which reuse the storage of the caller instead of allocating results.
+	Destination-receiving methods (c.AddOf(a, b), ...) store the result in
+	the receiver c, which needs the dimensions of the result. The receiver
+	may be one of the operands.
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// element-wise operations of kernel
+const (
+	opCopy = iota
+	opAdd
+	opSub
+	opCWiseProd
+	opScale
+	opAXPY
+	opApply
+)
+
+// kernel describes an element-wise operation dst = op(x, y)
+// with the scalar alpha or the function f as parameter.
+type kernel struct {
+	op    int
+	alpha float64
+	f     func(float64) float64
+}
+
+// apply computes dst[i] = op(x[i], y[i]) for all i.
+func (k kernel) apply(dst, x, y []float64) {
+	x = x[:len(dst)]
+	switch k.op {
+	case opCopy:
+		copy(dst, x)
+	case opAdd:
+		y = y[:len(dst)]
+		for i := range dst {
+			dst[i] = x[i] + y[i]
+		}
+	case opSub:
+		y = y[:len(dst)]
+		for i := range dst {
+			dst[i] = x[i] - y[i]
+		}
+	case opCWiseProd:
+		y = y[:len(dst)]
+		for i := range dst {
+			dst[i] = x[i] * y[i]
+		}
+	case opScale:
+		for i := range dst {
+			dst[i] = k.alpha * x[i]
+		}
+	case opAXPY:
+		y = y[:len(dst)]
+		for i := range dst {
+			dst[i] = x[i] + k.alpha*y[i]
+		}
+	case opApply:
+		for i := range dst {
+			dst[i] = k.f(x[i])
+		}
+	}
+}
+
+// AddInPlace computes a = a + b.
+// Returns a DimensionError, if dimensions don't match.
+func (a *Matrix) AddInPlace(b *Matrix) (e error) {
+	e = a.AddOf(a, b)
+	return
+}
+
+// SubInPlace computes a = a - b.
+// Returns a DimensionError, if dimensions don't match.
+func (a *Matrix) SubInPlace(b *Matrix) (e error) {
+	e = a.SubOf(a, b)
+	return
+}
+
+// CWiseProdInPlace computes the component-wise product a = a .* b.
+// Returns a DimensionError, if dimensions don't match.
+func (a *Matrix) CWiseProdInPlace(b *Matrix) (e error) {
+	e = a.CWiseProdOf(a, b)
+	return
+}
+
+// ScaleInPlace computes a = factor * a.
+// Returns ErrInvalidArgument, if factor = inf, or NaN
+func (a *Matrix) ScaleInPlace(factor float64) (e error) {
+	e = a.ScaleOf(factor, a)
+	return
+}
+
+// ApplyFuncInPlace applies f on every entry of a.
+func (a *Matrix) ApplyFuncInPlace(f func(float64) float64) {
+	a.ApplyFuncOf(f, a)
+}
+
+// AXPY computes a = a + alpha * x.
+// Returns a DimensionError, if dimensions don't match.
+func (a *Matrix) AXPY(alpha float64, x *Matrix) (e error) {
+	// check if dimensions match
+	if a.rows != x.rows || a.cols != x.cols {
+		e = &DimensionError{Op: "AXPY", A: a.shape(), B: x.shape()}
+		return
+	}
+	a.zipOf(a, x, kernel{op: opAXPY, alpha: alpha}, Workers())
+	return
+}
+
+// CopyFrom copies the entries of b into c.
+// Returns a DimensionError, if dimensions don't match.
+func (c *Matrix) CopyFrom(b *Matrix) (e error) {
+	// check if dimensions match
+	if c.rows != b.rows || c.cols != b.cols {
+		e = &DimensionError{Op: "CopyFrom", A: c.shape(), B: b.shape()}
+		return
+	}
+	c.zipOf(b, nil, kernel{op: opCopy}, Workers())
+	return
+}
+
+// AddOf computes c = a + b.
+// Returns a DimensionError, if dimensions don't match.
+func (c *Matrix) AddOf(a, b *Matrix) (e error) {
+	// check if dimensions match
+	if e = c.checkZip("AddOf", a, b); e != nil {
+		return
+	}
+	c.zipOf(a, b, kernel{op: opAdd}, Workers())
+	return
+}
+
+// SubOf computes c = a - b.
+// Returns a DimensionError, if dimensions don't match.
+func (c *Matrix) SubOf(a, b *Matrix) (e error) {
+	// check if dimensions match
+	if e = c.checkZip("SubOf", a, b); e != nil {
+		return
+	}
+	c.zipOf(a, b, kernel{op: opSub}, Workers())
+	return
+}
+
+// CWiseProdOf computes the component-wise product c = a .* b.
+// Returns a DimensionError, if dimensions don't match.
+func (c *Matrix) CWiseProdOf(a, b *Matrix) (e error) {
+	// check if dimensions match
+	if e = c.checkZip("CWiseProdOf", a, b); e != nil {
+		return
+	}
+	c.zipOf(a, b, kernel{op: opCWiseProd}, Workers())
+	return
+}
+
+// ScaleOf computes c = factor * a.
+// Returns ErrInvalidArgument, if factor = inf, or NaN
+// and a DimensionError, if dimensions don't match.
+func (c *Matrix) ScaleOf(factor float64, a *Matrix) (e error) {
+	// check if factor is valid
+	if math.IsNaN(factor) || math.IsInf(factor, 0) {
+		e = fmt.Errorf("%w: scale factor %v", ErrInvalidArgument, factor)
+		return
+	}
+	// check if dimensions match
+	if c.rows != a.rows || c.cols != a.cols {
+		e = &DimensionError{Op: "ScaleOf", A: c.shape(), B: a.shape()}
+		return
+	}
+	c.zipOf(a, nil, kernel{op: opScale, alpha: factor}, Workers())
+	return
+}
+
+// ApplyFuncOf stores the entries of a after applying func f in c.
+// Returns a DimensionError, if dimensions don't match.
+func (c *Matrix) ApplyFuncOf(f func(float64) float64, a *Matrix) (e error) {
+	// check if dimensions match
+	if c.rows != a.rows || c.cols != a.cols {
+		e = &DimensionError{Op: "ApplyFuncOf", A: c.shape(), B: a.shape()}
+		return
+	}
+	c.zipOf(a, nil, kernel{op: opApply, f: f}, 1)
+	return
+}
+
+// MulOf computes the matrix product c = a * b.
+// Returns a DimensionError, if dimensions don't match.
+func (c *Matrix) MulOf(a, b *Matrix) (e error) {
+	// check if dimensions match
+	if a.cols != b.rows {
+		e = &DimensionError{Op: "MulOf", A: a.shape(), B: b.shape()}
+		return
+	}
+	if c.rows != a.rows || c.cols != b.cols {
+		e = &DimensionError{Op: "MulOf", A: c.shape(), B: []int{a.rows, b.cols}}
+		return
+	}
+	// the product reads a and b while writing c, so it needs a temporary
+	// result if they overlap
+	if c.cstride != 1 || sameStorage(c.entries, a.entries) || sameStorage(c.entries, b.entries) {
+		tmp, _ := ZeroMat(c.rows, c.cols)
+		tmp.mulAdd(a, b)
+		c.CopyFrom(tmp)
+		return
+	}
+	for i := 0; i < c.rows; i++ {
+		cRow := c.row(i)
+		for j := range cRow {
+			cRow[j] = 0
+		}
+	}
+	c.mulAdd(a, b)
+	return
+}
+
+// AddInPlace computes a = a + b.
+// Returns a DimensionError, if sizes don't match.
+func (a *Vector) AddInPlace(b *Vector) (e error) {
+	e = a.AddOf(a, b)
+	return
+}
+
+// SubInPlace computes a = a - b.
+// Returns a DimensionError, if sizes don't match.
+func (a *Vector) SubInPlace(b *Vector) (e error) {
+	e = a.SubOf(a, b)
+	return
+}
+
+// CWiseProdInPlace computes the component-wise product a = a .* b.
+// Returns a DimensionError, if sizes don't match.
+func (a *Vector) CWiseProdInPlace(b *Vector) (e error) {
+	e = a.CWiseProdOf(a, b)
+	return
+}
+
+// ScaleInPlace computes a = factor * a.
+// Returns ErrInvalidArgument, if factor = inf, or NaN
+func (a *Vector) ScaleInPlace(factor float64) (e error) {
+	e = a.ScaleOf(factor, a)
+	return
+}
+
+// ApplyFuncInPlace applies f on every element of a.
+func (a *Vector) ApplyFuncInPlace(f func(float64) float64) {
+	a.ApplyFuncOf(f, a)
+}
+
+// AXPY computes a = a + alpha * x.
+// Returns a DimensionError, if sizes don't match.
+func (a *Vector) AXPY(alpha float64, x *Vector) (e error) {
+	// check sizes
+	if a.Size() != x.Size() {
+		e = &DimensionError{Op: "AXPY", A: a.shape(), B: x.shape()}
+		return
+	}
+	a.zipOf(a, x, kernel{op: opAXPY, alpha: alpha}, Workers())
+	return
+}
+
+// CopyFrom copies the elements of b into c.
+// Returns a DimensionError, if sizes don't match.
+func (c *Vector) CopyFrom(b *Vector) (e error) {
+	// check sizes
+	if c.Size() != b.Size() {
+		e = &DimensionError{Op: "CopyFrom", A: c.shape(), B: b.shape()}
+		return
+	}
+	c.zipOf(b, nil, kernel{op: opCopy}, Workers())
+	return
+}
+
+// AddOf computes c = a + b.
+// Returns a DimensionError, if sizes don't match.
+func (c *Vector) AddOf(a, b *Vector) (e error) {
+	// check sizes
+	if e = c.checkZip("AddOf", a, b); e != nil {
+		return
+	}
+	c.zipOf(a, b, kernel{op: opAdd}, Workers())
+	return
+}
+
+// SubOf computes c = a - b.
+// Returns a DimensionError, if sizes don't match.
+func (c *Vector) SubOf(a, b *Vector) (e error) {
+	// check sizes
+	if e = c.checkZip("SubOf", a, b); e != nil {
+		return
+	}
+	c.zipOf(a, b, kernel{op: opSub}, Workers())
+	return
+}
+
+// CWiseProdOf computes the component-wise product c = a .* b.
+// Returns a DimensionError, if sizes don't match.
+func (c *Vector) CWiseProdOf(a, b *Vector) (e error) {
+	// check sizes
+	if e = c.checkZip("CWiseProdOf", a, b); e != nil {
+		return
+	}
+	c.zipOf(a, b, kernel{op: opCWiseProd}, Workers())
+	return
+}
+
+// ScaleOf computes c = factor * a.
+// Returns ErrInvalidArgument, if factor = inf, or NaN
+// and a DimensionError, if sizes don't match.
+func (c *Vector) ScaleOf(factor float64, a *Vector) (e error) {
+	// check if factor is valid
+	if math.IsNaN(factor) || math.IsInf(factor, 0) {
+		e = fmt.Errorf("%w: scale factor %v", ErrInvalidArgument, factor)
+		return
+	}
+	// check sizes
+	if c.Size() != a.Size() {
+		e = &DimensionError{Op: "ScaleOf", A: c.shape(), B: a.shape()}
+		return
+	}
+	c.zipOf(a, nil, kernel{op: opScale, alpha: factor}, Workers())
+	return
+}
+
+// ApplyFuncOf stores the elements of a after applying func f in c.
+// Returns a DimensionError, if sizes don't match.
+func (c *Vector) ApplyFuncOf(f func(float64) float64, a *Vector) (e error) {
+	// check sizes
+	if c.Size() != a.Size() {
+		e = &DimensionError{Op: "ApplyFuncOf", A: c.shape(), B: a.shape()}
+		return
+	}
+	c.zipOf(a, nil, kernel{op: opApply, f: f}, 1)
+	return
+}
+
+// MulVecOf computes the matrix-vector product c = a * v.
+// Returns a DimensionError, if dimensions don't match.
+func (c *Vector) MulVecOf(a *Matrix, v *Vector) (e error) {
+	// check if dimensions match
+	if a.cols != v.Size() {
+		e = &DimensionError{Op: "MulVecOf", A: a.shape(), B: v.shape()}
+		return
+	}
+	if c.Size() != a.rows {
+		e = &DimensionError{Op: "MulVecOf", A: c.shape(), B: []int{a.rows}}
+		return
+	}
+	// the product reads v while writing c
+	if sameStorage(c.entries, v.entries) {
+		v = v.CopyVec()
+	}
+	if sameStorage(c.entries, a.entries) {
+		a = a.CopyMat()
+	}
+	if chunks := min(numChunks(a.rows*a.cols, Workers(), minParallelWork), a.rows); chunks > 1 {
+		c.mulVecRowsParallel(a, v, chunks)
+		return
+	}
+	c.mulVecRows(a, v, 0, a.rows)
+	return
+}
+
+// checkZip checks that c, a and b have the same dimensions.
+func (c *Matrix) checkZip(op string, a, b *Matrix) (e error) {
+	if a.rows != b.rows || a.cols != b.cols {
+		e = &DimensionError{Op: op, A: a.shape(), B: b.shape()}
+		return
+	}
+	if c.rows != a.rows || c.cols != a.cols {
+		e = &DimensionError{Op: op, A: c.shape(), B: a.shape()}
+	}
+	return
+}
+
+// checkZip checks that c, a and b have the same size.
+func (c *Vector) checkZip(op string, a, b *Vector) (e error) {
+	if a.Size() != b.Size() {
+		e = &DimensionError{Op: op, A: a.shape(), B: b.shape()}
+		return
+	}
+	if c.Size() != a.Size() {
+		e = &DimensionError{Op: op, A: c.shape(), B: a.shape()}
+	}
+	return
+}
+
+// zipOf applies the element-wise kernel on the entries of c, a and b
+// (b may be nil) with up to workers goroutines.
+func (c *Matrix) zipOf(a, b *Matrix, k kernel, workers int) {
+	a = c.unaliased(a)
+	if b != nil {
+		b = c.unaliased(b)
+	}
+	// fast path: all entries at once
+	if c.isContiguous() && a.isContiguous() && (b == nil || b.isContiguous()) {
+		n := c.rows * c.cols
+		if chunks := numChunks(n, workers, minParallelWork); chunks > 1 {
+			c.zipFlatParallel(a, b, k, chunks)
+			return
+		}
+		var y []float64
+		if b != nil {
+			y = b.entries[:n]
+		}
+		k.apply(c.entries[:n], a.entries[:n], y)
+		return
+	}
+	// row by row
+	if chunks := numChunks(c.rows, workers, max(1, minParallelWork/c.cols)); chunks > 1 {
+		c.zipRowsParallel(a, b, k, chunks)
+		return
+	}
+	c.zipRows(a, b, k, 0, c.rows)
+}
+
+// zipFlatParallel applies the kernel on the contiguous matrices c, a and b
+// split into chunks, which are processed concurrently.
+func (c *Matrix) zipFlatParallel(a, b *Matrix, k kernel, chunks int) {
+	parallelFor(c.rows*c.cols, chunks, func(lo, hi int) {
+		var y []float64
+		if b != nil {
+			y = b.entries[lo:hi]
+		}
+		k.apply(c.entries[lo:hi], a.entries[lo:hi], y)
+	})
+}
+
+// zipRowsParallel applies the kernel on the rows of c, a and b
+// split into chunks, which are processed concurrently.
+func (c *Matrix) zipRowsParallel(a, b *Matrix, k kernel, chunks int) {
+	parallelFor(c.rows, chunks, func(lo, hi int) {
+		c.zipRows(a, b, k, lo, hi)
+	})
+}
+
+// zipRows applies the kernel on the rows lo, ..., hi-1.
+// Rows with a column stride != 1 are gathered into buffers.
+func (c *Matrix) zipRows(a, b *Matrix, k kernel, lo, hi int) {
+	var bufC, bufA, bufB []float64
+	for i := lo; i < hi; i++ {
+		var y []float64
+		if b != nil {
+			y = b.rowInto(i, &bufB)
+		}
+		if c.cstride == 1 {
+			k.apply(c.row(i), a.rowInto(i, &bufA), y)
+			continue
+		}
+		dst := c.rowInto(i, &bufC)
+		k.apply(dst, a.rowInto(i, &bufA), y)
+		for j := range dst {
+			c.setEntry(i, j, dst[j])
+		}
+	}
+}
+
+// zipOf applies the element-wise kernel on the elements of c, a and b
+// (b may be nil) with up to workers goroutines.
+// Vectors with inc != 1 are gathered into buffers.
+func (c *Vector) zipOf(a, b *Vector, k kernel, workers int) {
+	a = c.unaliased(a)
+	var y []float64
+	if b != nil {
+		y = c.unaliased(b).slice()
+	}
+	dst := c.entries
+	if c.inc != 1 {
+		dst = c.Slice()
+	}
+	if chunks := numChunks(len(dst), workers, minParallelWork); chunks > 1 {
+		zipSlicesParallel(dst, a.slice(), y, k, chunks)
+	} else {
+		k.apply(dst, a.slice(), y)
+	}
+	if c.inc != 1 {
+		for i := range dst {
+			c.setAt(i, dst[i])
+		}
+	}
+}
+
+// zipSlicesParallel applies the kernel on the slices dst, x and y
+// split into chunks, which are processed concurrently.
+func zipSlicesParallel(dst, x, y []float64, k kernel, chunks int) {
+	parallelFor(len(dst), chunks, func(lo, hi int) {
+		var ys []float64
+		if y != nil {
+			ys = y[lo:hi]
+		}
+		k.apply(dst[lo:hi], x[lo:hi], ys)
+	})
+}
+
+// rowInto returns the i-th row of a as slice. If the row isn't stored
+// contiguously, it is copied into *buf, which is allocated on demand.
+func (a *Matrix) rowInto(i int, buf *[]float64) []float64 {
+	if a.cstride == 1 {
+		return a.row(i)
+	}
+	if len(*buf) != a.cols {
+		*buf = make([]float64, a.cols)
+	}
+	for j := range *buf {
+		(*buf)[j] = a.getEntry(i, j)
+	}
+	return *buf
+}
+
+// slice returns the elements of a as slice, which shares
+// the storage of a, if a.inc = 1.
+func (a *Vector) slice() []float64 {
+	if a.inc == 1 {
+		return a.entries
+	}
+	return a.Slice()
+}
+
+// unaliased returns a, or a copy of a, if a shares storage with c
+// in a different layout. Element-wise operations may then overwrite
+// entries of a before reading them.
+func (c *Matrix) unaliased(a *Matrix) *Matrix {
+	if !sameStorage(c.entries, a.entries) {
+		return a
+	}
+	if &c.entries[0] == &a.entries[0] && c.rstride == a.rstride && c.cstride == a.cstride {
+		return a
+	}
+	return a.CopyMat()
+}
+
+// unaliased returns a, or a copy of a, if a shares storage with c
+// in a different layout.
+func (c *Vector) unaliased(a *Vector) *Vector {
+	if !sameStorage(c.entries, a.entries) {
+		return a
+	}
+	if &c.entries[0] == &a.entries[0] && c.inc == a.inc {
+		return a
+	}
+	return a.CopyVec()
+}
+
+// sameStorage reports whether x and y are parts of the same array.
+// Slices of the same array share the last element of their capacity.
+func sameStorage(x, y []float64) bool {
+	if cap(x) == 0 || cap(y) == 0 {
+		return false
+	}
+	return &x[:cap(x)][cap(x)-1] == &y[:cap(y)][cap(y)-1]
+}
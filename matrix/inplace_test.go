@@ -0,0 +1,120 @@
+package matrix
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMatrixOf(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+	a := randMat(4, 5, rng)
+	b := randMat(4, 5, rng)
+	c, _ := ZeroMat(4, 5)
+	tests := []struct {
+		name string
+		of   func() error
+		want *Matrix
+	}{
+		{"AddOf", func() error { return c.AddOf(a, b) }, a.Add(b)},
+		{"SubOf", func() error { return c.SubOf(a, b) }, a.Sub(b)},
+		{"CWiseProdOf", func() error { return c.CWiseProdOf(a, b) }, a.CWiseProd(b)},
+		{"ScaleOf", func() error { return c.ScaleOf(3, a) }, a.Scale(3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if e := tt.of(); e != nil {
+				t.Fatal(e)
+			}
+			matClose(t, tt.name, c, tt.want, 0)
+		})
+	}
+	a2 := a.CopyMat()
+	a2.AddInPlace(b)
+	matClose(t, "AddInPlace", a2, a.Add(b), 0)
+	a2 = a.CopyMat()
+	a2.AXPY(2, b)
+	matClose(t, "AXPY", a2, a.Add(b.Scale(2)), 1e-15)
+}
+
+func TestInPlaceAliasing(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+	sq := randMat(5, 5, rng)
+	ref := sq.Add(naiveT(sq))
+	sq.AddInPlace(sq.T())
+	matClose(t, "A += A^T", sq, ref, 0)
+	// a transposed block as destination leaves the rest untouched
+	big := randMat(6, 6, rng)
+	bigc := big.CopyMat()
+	blk := big.BlockView(1, 1, 4, 5).T()
+	x := randMat(5, 4, rng)
+	blk.AddInPlace(x)
+	matClose(t, "block", blk, naiveT(bigc.GetBlock(1, 1, 4, 5)).Add(x), 0)
+	if big.Get(0, 0) != bigc.Get(0, 0) || big.Get(5, 5) != bigc.Get(5, 5) {
+		t.Fatal("entries outside the block modified")
+	}
+}
+
+func TestMulOf(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+	p := randMat(4, 3, rng)
+	q := randMat(3, 4, rng)
+	d, _ := ZeroMat(4, 4)
+	d.Set(0, 0, 99)
+	if e := d.MulOf(p, q); e != nil {
+		t.Fatal(e)
+	}
+	matClose(t, "MulOf", d, naiveMul(p, q), 1e-12)
+	s := randMat(4, 4, rng)
+	ref := naiveMul(s, s)
+	s.MulOf(s, s)
+	matClose(t, "S = S*S", s, ref, 1e-12)
+	s = randMat(4, 4, rng)
+	ref = naiveMul(s, s)
+	s.T().MulOf(s.T(), s.T())
+	matClose(t, "S^T = S^T*S^T", s, ref, 1e-12)
+	if e := d.MulOf(p, p); e == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestVectorInPlace(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+	u := randVec(5, rng)
+	v := randVec(5, rng)
+	w := ZeroVec(5)
+	w.AddOf(u, v)
+	vecClose(t, "AddOf", w, u.Add(v), 0)
+	w.CopyFrom(u)
+	w.AXPY(-1, v)
+	vecClose(t, "AXPY", w, u.Sub(v), 0)
+	m := randMat(3, 5, rng)
+	z := ZeroVec(3)
+	z.MulVecOf(m, u)
+	vecClose(t, "MulVecOf", z, m.MulVec(u), 1e-14)
+	sq := randMat(5, 5, rng)
+	cv := sq.ColView(0)
+	want := sq.MulVec(cv.CopyVec())
+	cv.MulVecOf(sq, cv)
+	vecClose(t, "aliased MulVecOf", cv, want, 1e-14)
+	if e := u.AddInPlace(z); e == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestInPlaceAllocs(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+	a := randMat(40, 50, rng)
+	b := randMat(40, 50, rng)
+	v := randVec(50, rng)
+	w := randVec(50, rng)
+	n := testing.AllocsPerRun(100, func() {
+		a.AddInPlace(b)
+		a.AXPY(0.5, b)
+		a.ScaleInPlace(0.9)
+		v.AXPY(2, w)
+		v.ScaleInPlace(0.1)
+	})
+	if n != 0 {
+		t.Fatal("allocations", n)
+	}
+}
@@ -6,12 +6,13 @@ package matrix
 
 import (
 	"fmt"
+	"io"
+	"strings"
 )
 
 // implements the Stringer interface for matrix type
 func (m Matrix) String() string {
 	var s string
-	c := m.cols
 	// print dimension
 	s = "---------------------------------------------------------------------------------------------------------------------\n"
 	s = s + fmt.Sprintf("Dimension: Rows: %d \t Cols: %d \n", m.rows, m.cols)
@@ -21,7 +22,7 @@ func (m Matrix) String() string {
 	for i := 0; i < m.rows; i++ {
 		localString := ""
 		for j := 0; j < m.cols; j++ {
-			localString = localString + fmt.Sprintf("%10.4g ", m.entries[c*i+j])
+			localString = localString + fmt.Sprintf("%10.4g ", m.getEntry(i, j))
 		}
 		s = s + localString + "\n"
 	}
@@ -34,9 +35,118 @@ func (v Vector) String() string {
 	var s string
 	// print size
 	s = "---------------------------------------------------------------------------------------------------------------------\n"
-	s = s + fmt.Sprintf("Size: %d\n", len(v.entries))
+	s = s + fmt.Sprintf("Size: %d\n", v.Size())
 	s = s + "Vector: "
-	s = s + fmt.Sprintf("%v\n", v.entries)
+	s = s + fmt.Sprintf("%v\n", v.Slice())
 	s = s + "---------------------------------------------------------------------------------------------------------------------\n"
 	return s
 }
+
+// implements the fmt.Formatter interface for matrix type.
+// %v and the verbs %e, %E, %f, %F, %g and %G print the entries as aligned
+// grid, one row per line, honouring flags, width and precision, e.g. with
+// %.3f or %8.2e. %v prints the entries like %g. Only %s prints the
+// String() banner.
+func (m Matrix) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 's':
+		io.WriteString(s, m.String())
+	case verb == 'v' || strings.ContainsRune("eEfFgG", verb):
+		io.WriteString(s, m.grid(entryFormat(s, verb)))
+	default:
+		fmt.Fprintf(s, "%%!%c(matrix.Matrix=%dx%d)", verb, m.rows, m.cols)
+	}
+}
+
+// implements the fmt.Formatter interface for vector type.
+// %v and the verbs %e, %E, %f, %F, %g and %G print the elements on a single
+// line, honouring flags, width and precision, e.g. [1.000 2.000] with %.3f.
+// %v prints the elements like %g. Only %s prints the String() banner.
+func (v Vector) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 's':
+		io.WriteString(s, v.String())
+	case verb == 'v' || strings.ContainsRune("eEfFgG", verb):
+		io.WriteString(s, v.Compact(&FormatOptions{Format: entryFormat(s, verb)}))
+	default:
+		fmt.Fprintf(s, "%%!%c(matrix.Vector=%d)", verb, v.Size())
+	}
+}
+
+// entryFormat returns the format of a single entry for the state s,
+// %v is printed as %g
+func entryFormat(s fmt.State, verb rune) string {
+	if verb == 'v' {
+		verb = 'g'
+	}
+	format := "%"
+	for _, flag := range "+- 0#" {
+		if s.Flag(int(flag)) {
+			format += string(flag)
+		}
+	}
+	if width, ok := s.Width(); ok {
+		format += fmt.Sprint(width)
+	}
+	if prec, ok := s.Precision(); ok {
+		format += "." + fmt.Sprint(prec)
+	}
+	return format + string(verb)
+}
+
+// implements the Stringer interface for generic matrix type
+func (m GenMatrix[T]) String() string {
+	var s string
+	// print dimension
+	s = "---------------------------------------------------------------------------------------------------------------------\n"
+	s = s + fmt.Sprintf("Dimension: Rows: %d \t Cols: %d \n", m.rows, m.cols)
+	s = s + "Matrix: \n"
+
+	// print matrix
+	for i := 0; i < m.rows; i++ {
+		localString := ""
+		for j := 0; j < m.cols; j++ {
+			localString = localString + fmt.Sprintf("%10v ", m.getEntry(i, j))
+		}
+		s = s + localString + "\n"
+	}
+	s = s + "---------------------------------------------------------------------------------------------------------------------\n"
+	return s
+}
+
+// implements the Stringer interface for generic vector type
+func (v GenVector[T]) String() string {
+	var s string
+	// print size
+	s = "---------------------------------------------------------------------------------------------------------------------\n"
+	s = s + fmt.Sprintf("Size: %d\n", v.Size())
+	s = s + "Vector: "
+	s = s + fmt.Sprintf("%v\n", v.Slice())
+	s = s + "---------------------------------------------------------------------------------------------------------------------\n"
+	return s
+}
+
+// implements the Stringer interface for complex matrix type
+func (m CMatrix) String() string {
+	var s string
+	// print dimension
+	s = "---------------------------------------------------------------------------------------------------------------------\n"
+	s = s + fmt.Sprintf("Dimension: Rows: %d \t Cols: %d \n", m.rows, m.cols)
+	s = s + "Matrix: \n"
+
+	// print matrix
+	for i := 0; i < m.rows; i++ {
+		localString := ""
+		for j := 0; j < m.cols; j++ {
+			localString = localString + fmt.Sprintf("%20.4g ", m.entries[i*m.cols+j])
+		}
+		s = s + localString + "\n"
+	}
+	s = s + "---------------------------------------------------------------------------------------------------------------------\n"
+	return s
+}
+
+// implements the Stringer interface for complex vector type
+func (v CVector) String() string {
+	return GenVector[complex128](v).String()
+}
@@ -0,0 +1,344 @@
+/*	This file implements the iterative Krylov solvers CG, BiCGSTAB and GMRES
+	for linear systems a * x = b, where a only has to provide MulVec
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// default relative residual tolerance of the iterative solvers
+const defaultIterTol = 1e-10
+
+// CG solves a * x = b for a symmetric positive definite operator a with the
+// (preconditioned) conjugate gradient method. The preconditioner has to be
+// symmetric positive definite too. s may be nil for the default settings.
+// Returns ErrNoConvergence together with the last iterate, if the tolerance
+// isn't reached within MaxIter iterations, and ErrNotPositiveDefinite,
+// if a breakdown shows that a isn't positive definite.
+func CG(a Operator, b *Vector, s *IterSettings) (x *Vector, st *IterStats, e error) {
+	cfg, x, r, bnorm, st, e := iterSetup("CG", a, b, s)
+	if e != nil || st.Converged {
+		return
+	}
+	z, e := precondApply("CG", cfg.Precond, r)
+	if e != nil {
+		return
+	}
+	p := z.CopyVec()
+	rz := r.Dot(z)
+	for st.Iterations < cfg.MaxIter {
+		ap, err := operatorMulVec("CG", a, p)
+		if err != nil {
+			e = err
+			return
+		}
+		pap := p.Dot(ap)
+		if pap <= 0 {
+			e = ErrNotPositiveDefinite
+			return
+		}
+		alpha := rz / pap
+		x.AXPY(alpha, p)
+		r.AXPY(-alpha, ap)
+		if st.record(r.Norm2()/bnorm, cfg.Tol) {
+			return
+		}
+		if z, e = precondApply("CG", cfg.Precond, r); e != nil {
+			return
+		}
+		rzNew := r.Dot(z)
+		beta := rzNew / rz
+		rz = rzNew
+		// p = z + beta * p
+		p.ScaleInPlace(beta)
+		p.AddInPlace(z)
+	}
+	e = ErrNoConvergence
+	return
+}
+
+// BiCGSTAB solves a * x = b for a general square operator a with the
+// right preconditioned stabilized bi-conjugate gradient method.
+// s may be nil for the default settings.
+// Returns ErrNoConvergence together with the last iterate, if the tolerance
+// isn't reached within MaxIter iterations or the method breaks down.
+func BiCGSTAB(a Operator, b *Vector, s *IterSettings) (x *Vector, st *IterStats, e error) {
+	cfg, x, r, bnorm, st, e := iterSetup("BiCGSTAB", a, b, s)
+	if e != nil || st.Converged {
+		return
+	}
+	n := b.Size()
+	rhat := r.CopyVec()
+	p := ZeroVec(n)
+	v := ZeroVec(n)
+	var rho, alpha, omega float64 = 1, 1, 1
+	for st.Iterations < cfg.MaxIter {
+		rhoNew := rhat.Dot(r)
+		if rhoNew == 0 {
+			e = fmt.Errorf("%w: BiCGSTAB breakdown (rho = 0)", ErrNoConvergence)
+			return
+		}
+		beta := (rhoNew / rho) * (alpha / omega)
+		rho = rhoNew
+		// p = r + beta * (p - omega * v)
+		p.AXPY(-omega, v)
+		p.ScaleInPlace(beta)
+		p.AddInPlace(r)
+		phat, err := precondApply("BiCGSTAB", cfg.Precond, p)
+		if err != nil {
+			e = err
+			return
+		}
+		if v, e = operatorMulVec("BiCGSTAB", a, phat); e != nil {
+			return
+		}
+		rv := rhat.Dot(v)
+		if rv == 0 {
+			e = fmt.Errorf("%w: BiCGSTAB breakdown (rhat * v = 0)", ErrNoConvergence)
+			return
+		}
+		alpha = rho / rv
+		x.AXPY(alpha, phat)
+		// s = r - alpha * v, stored in r
+		r.AXPY(-alpha, v)
+		res := r.Norm2() / bnorm
+		if res <= cfg.Tol {
+			st.record(res, cfg.Tol)
+			return
+		}
+		shat, err := precondApply("BiCGSTAB", cfg.Precond, r)
+		if err != nil {
+			e = err
+			return
+		}
+		t, err := operatorMulVec("BiCGSTAB", a, shat)
+		if err != nil {
+			e = err
+			return
+		}
+		tt := t.Dot(t)
+		if tt == 0 {
+			e = fmt.Errorf("%w: BiCGSTAB breakdown (t = 0)", ErrNoConvergence)
+			return
+		}
+		omega = t.Dot(r) / tt
+		x.AXPY(omega, shat)
+		r.AXPY(-omega, t)
+		if st.record(r.Norm2()/bnorm, cfg.Tol) {
+			return
+		}
+		if omega == 0 {
+			e = fmt.Errorf("%w: BiCGSTAB breakdown (omega = 0)", ErrNoConvergence)
+			return
+		}
+	}
+	e = ErrNoConvergence
+	return
+}
+
+// GMRES solves a * x = b for a general square operator a with the right
+// preconditioned restarted generalized minimal residual method.
+// The residuals in the history are the estimates of the Arnoldi process.
+// s may be nil for the default settings.
+// Returns ErrNoConvergence together with the last iterate, if the tolerance
+// isn't reached within MaxIter iterations.
+func GMRES(a Operator, b *Vector, s *IterSettings) (x *Vector, st *IterStats, e error) {
+	cfg, x, r, bnorm, st, e := iterSetup("GMRES", a, b, s)
+	if e != nil || st.Converged {
+		return
+	}
+	m := cfg.Restart
+	// Krylov basis, Hessenberg matrix and Givens rotations
+	basis := make([]*Vector, m+1)
+	h := make([][]float64, m+1)
+	for i := range h {
+		h[i] = make([]float64, m)
+	}
+	cs := make([]float64, m)
+	sn := make([]float64, m)
+	g := make([]float64, m+1)
+	for {
+		beta := r.Norm2()
+		basis[0], _ = r.ScaleSafe(1 / beta)
+		for i := range g {
+			g[i] = 0
+		}
+		g[0] = beta
+		k := 0
+		done := false
+		for k < m && st.Iterations < cfg.MaxIter {
+			zk, err := precondApply("GMRES", cfg.Precond, basis[k])
+			if err != nil {
+				e = err
+				return
+			}
+			w, err := operatorMulVec("GMRES", a, zk)
+			if err != nil {
+				e = err
+				return
+			}
+			// modified Gram-Schmidt
+			for i := 0; i <= k; i++ {
+				h[i][k] = w.Dot(basis[i])
+				w.AXPY(-h[i][k], basis[i])
+			}
+			hNext := w.Norm2()
+			// apply previous rotations to the new column
+			for i := 0; i < k; i++ {
+				t := cs[i]*h[i][k] + sn[i]*h[i+1][k]
+				h[i+1][k] = -sn[i]*h[i][k] + cs[i]*h[i+1][k]
+				h[i][k] = t
+			}
+			// new rotation eliminating hNext
+			rho := math.Hypot(h[k][k], hNext)
+			if rho == 0 {
+				e = fmt.Errorf("%w: GMRES breakdown", ErrNoConvergence)
+				return
+			}
+			cs[k] = h[k][k] / rho
+			sn[k] = hNext / rho
+			h[k][k] = rho
+			g[k+1] = -sn[k] * g[k]
+			g[k] = cs[k] * g[k]
+			k++
+			done = st.record(math.Abs(g[k])/bnorm, cfg.Tol)
+			// lucky breakdown: the solution lies in the Krylov space
+			if done || hNext == 0 {
+				break
+			}
+			w.ScaleInPlace(1 / hNext)
+			basis[k] = w
+		}
+		// solve upper triangular system h * y = g
+		y := make([]float64, k)
+		for i := k - 1; i >= 0; i-- {
+			sum := g[i]
+			for j := i + 1; j < k; j++ {
+				sum -= h[i][j] * y[j]
+			}
+			y[i] = sum / h[i][i]
+		}
+		// x = x + M^-1 * (basis * y)
+		u := ZeroVec(b.Size())
+		for i := 0; i < k; i++ {
+			u.AXPY(y[i], basis[i])
+		}
+		if u, e = precondApply("GMRES", cfg.Precond, u); e != nil {
+			return
+		}
+		x.AddInPlace(u)
+		if done {
+			return
+		}
+		if st.Iterations >= cfg.MaxIter {
+			e = ErrNoConvergence
+			return
+		}
+		// restart with the true residual
+		ax, err := operatorMulVec("GMRES", a, x)
+		if err != nil {
+			e = err
+			return
+		}
+		r.SubOf(b, ax)
+		if res := r.Norm2() / bnorm; res <= cfg.Tol {
+			st.Residual = res
+			st.Converged = true
+			return
+		}
+	}
+}
+
+// iterSetup checks the arguments of an iterative solver, fills in the default
+// settings and computes the initial iterate and residual.
+func iterSetup(op string, a Operator, b *Vector, s *IterSettings) (cfg IterSettings, x, r *Vector, bnorm float64, st *IterStats, e error) {
+	n := b.Size()
+	if s != nil {
+		cfg = *s
+	}
+	// check settings
+	if cfg.Tol < 0 || math.IsNaN(cfg.Tol) || cfg.MaxIter < 0 || cfg.Restart < 0 {
+		e = fmt.Errorf("%w: settings of %s", ErrInvalidArgument, op)
+		return
+	}
+	if cfg.Tol == 0 {
+		cfg.Tol = defaultIterTol
+	}
+	if cfg.MaxIter == 0 {
+		cfg.MaxIter = 10 * n
+	}
+	if cfg.Restart == 0 {
+		cfg.Restart = min(n, 30)
+	}
+	// initial iterate
+	if cfg.X0 != nil {
+		if cfg.X0.Size() != n {
+			e = &DimensionError{Op: op, A: cfg.X0.shape(), B: b.shape()}
+			return
+		}
+		x = cfg.X0.CopyVec()
+	} else {
+		x = ZeroVec(n)
+	}
+	// initial residual
+	ax, e := operatorMulVec(op, a, x)
+	if e != nil {
+		return
+	}
+	r = b.Sub(ax)
+	st = new(IterStats)
+	bnorm = b.Norm2()
+	if bnorm == 0 {
+		// the solution of a * x = 0 is x = 0
+		x = ZeroVec(n)
+		st.History = []float64{0}
+		st.Converged = true
+		return
+	}
+	st.History = []float64{r.Norm2() / bnorm}
+	st.Residual = st.History[0]
+	st.Converged = st.Residual <= cfg.Tol
+	return
+}
+
+// record adds the relative residual res of a new iteration to the statistics
+// and returns whether the tolerance is reached.
+func (st *IterStats) record(res, tol float64) bool {
+	st.Iterations++
+	st.Residual = res
+	st.History = append(st.History, res)
+	st.Converged = res <= tol
+	return st.Converged
+}
+
+// operatorMulVec returns a * v, or an error if a isn't square of size v.Size()
+func operatorMulVec(op string, a Operator, v *Vector) (w *Vector, e error) {
+	w = a.MulVec(v)
+	if w == nil {
+		e = &DimensionError{Op: op, A: v.shape()}
+	} else if w.Size() != v.Size() {
+		e = &DimensionError{Op: op, A: v.shape(), B: w.shape()}
+		w = nil
+	}
+	return
+}
+
+// precondApply returns M^-1 * r, or a copy of r without preconditioner
+func precondApply(op string, p Preconditioner, r *Vector) (z *Vector, e error) {
+	if p == nil {
+		z = r.CopyVec()
+		return
+	}
+	z = p.Apply(r)
+	if z == nil {
+		e = &DimensionError{Op: op, A: r.shape()}
+	} else if z.Size() != r.Size() {
+		e = &DimensionError{Op: op, A: r.shape(), B: z.shape()}
+		z = nil
+	}
+	return
+}
@@ -0,0 +1,126 @@
+package matrix
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// laplace returns the 2D Laplacian on a k x k grid, conv adds a
+// convection term which makes it nonsymmetric
+func laplace(k int, conv float64) *CSR {
+	n := k * k
+	c, _ := NewCOO(n, n)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			p := i*k + j
+			c.Append(p, p, 4)
+			if i > 0 {
+				c.Append(p, p-k, -1-conv)
+			}
+			if i < k-1 {
+				c.Append(p, p+k, -1+conv)
+			}
+			if j > 0 {
+				c.Append(p, p-1, -1)
+			}
+			if j < k-1 {
+				c.Append(p, p+1, -1)
+			}
+		}
+	}
+	return c.ToCSR()
+}
+
+type solver func(a Operator, b *Vector, s *IterSettings) (*Vector, *IterStats, error)
+
+func TestKrylovConvergence(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	sym := laplace(12, 0)
+	nonsym := laplace(12, 0.4)
+	jac, _ := NewJacobi(sym.Diag())
+	ilu, _ := NewILU0(sym)
+	iluNonsym, _ := NewILU0(nonsym)
+	tests := []struct {
+		name   string
+		solve  solver
+		a      *CSR
+		op     Operator
+		precon Preconditioner
+	}{
+		{"CG", CG, sym, sym, nil},
+		{"CG Jacobi", CG, sym, sym, jac},
+		{"CG ILU0", CG, sym, sym, ilu},
+		{"GMRES dense", GMRES, sym, sym.ToDense(), nil},
+		{"GMRES Jacobi", GMRES, sym, sym, jac},
+		{"BiCGSTAB", BiCGSTAB, nonsym, nonsym, nil},
+		{"BiCGSTAB ILU0", BiCGSTAB, nonsym, nonsym, iluNonsym},
+		{"GMRES nonsymmetric", GMRES, nonsym, nonsym, nil},
+		{"GMRES nonsymmetric ILU0", GMRES, nonsym, nonsym.ToCSC(), iluNonsym},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xs := randVec(tt.a.Rows(), rng)
+			b := tt.a.MulVec(xs)
+			x, st, e := tt.solve(tt.op, b, &IterSettings{Precond: tt.precon, Restart: 20})
+			if e != nil || !st.Converged {
+				t.Fatal(e, st.Iterations)
+			}
+			if st.Residual > 1e-10 || len(st.History) != st.Iterations+1 {
+				t.Fatal(st.Residual, len(st.History))
+			}
+			vecClose(t, tt.name, x, xs, 1e-7)
+		})
+	}
+}
+
+func TestKrylovErrors(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	a := laplace(12, 0)
+	n := a.Rows()
+	b := a.MulVec(randVec(n, rng))
+	_, st, e := CG(a, b, &IterSettings{MaxIter: 3})
+	if !errors.Is(e, ErrNoConvergence) || st.Iterations != 3 || len(st.History) != 4 {
+		t.Fatal(e, st.Iterations)
+	}
+	if _, _, e := CG(a, randVec(3, rng), nil); !errors.Is(e, ErrDimensionMismatch) {
+		t.Fatal(e)
+	}
+	// an operator returning vectors of the wrong size
+	var de *DimensionError
+	if _, _, e := CG(badOp{}, VecFromSlice([]float64{1, 2}), nil); !errors.As(e, &de) || de.B[0] != 3 {
+		t.Fatal(e)
+	}
+	x, st, e := GMRES(a, ZeroVec(n), nil)
+	if e != nil || !st.Converged || x.Size() != n {
+		t.Fatal("zero right hand side", e)
+	}
+}
+
+// badOp is an operator with a wrong result size
+type badOp struct{}
+
+func (badOp) MulVec(v *Vector) *Vector { return ZeroVec(v.Size() + 1) }
+
+func TestILU0Exact(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	// without zeros ILU(0) is the exact LU decomposition
+	d := randMat(6, 6, rng)
+	for i := 0; i < 6; i++ {
+		d.Set(i, i, d.Get(i, i)+10)
+	}
+	f, e := NewILU0(CSRFromDense(d))
+	if e != nil {
+		t.Fatal(e)
+	}
+	v := randVec(6, rng)
+	vecClose(t, "ILU0", f.Apply(d.MulVec(v)), v, 1e-10)
+	jac, e := NewJacobi(VecFromSlice([]float64{2, 4}))
+	if e != nil {
+		t.Fatal(e)
+	}
+	vecClose(t, "Jacobi", jac.Apply(VecFromSlice([]float64{1, 1})), VecFromSlice([]float64{0.5, 0.25}), 0)
+	if _, e := NewJacobi(VecFromSlice([]float64{1, 0})); e == nil {
+		t.Fatal("expected error for zero diagonal")
+	}
+}
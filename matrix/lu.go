@@ -0,0 +1,264 @@
+/*	This file implements the LU decomposition with partial pivoting
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"math"
+)
+
+// eps is the machine epsilon of float64
+const eps = 2.220446049250313e-16
+
+// NewLU computes the LU decomposition of the square matrix a,
+// such that P * a = L * U. The matrix a is not modified.
+// Singular matrices are factorized as well, but Solve and Inverse
+// return ErrSingular for them.
+func NewLU(a Mat) (f *LU, e error) {
+	f, e = newLU(asMatrix(a))
+	return
+}
+
+// newLU implements NewLU for a dense matrix
+func newLU(a *Matrix) (f *LU, e error) {
+	// check dimensions
+	if a.rows != a.cols {
+		e = ErrNotSquare
+		return
+	}
+	n := a.rows
+	// create factorization
+	f = new(LU)
+	f.lu = a.CopyMat()
+	f.pivot, f.sign, f.tol = luFactor(f.lu.entries, n, math.Abs)
+	return
+}
+
+// IsSingular reports whether the factorized matrix is singular.
+func (f *LU) IsSingular() bool {
+	return luSingular(f.lu.entries, f.lu.rows, f.tol, math.Abs)
+}
+
+// L returns the unit lower triangular factor.
+func (f *LU) L() (l *Matrix) {
+	n := f.lu.rows
+	l, _ = IdMat(n, n)
+	for i := 1; i < n; i++ {
+		for j := 0; j < i; j++ {
+			l.entries[i*n+j] = f.lu.entries[i*n+j]
+		}
+	}
+	return
+}
+
+// U returns the upper triangular factor.
+func (f *LU) U() (u *Matrix) {
+	n := f.lu.rows
+	u, _ = ZeroMat(n, n)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			u.entries[i*n+j] = f.lu.entries[i*n+j]
+		}
+	}
+	return
+}
+
+// Pivot returns the row permutation: row i of P * A is row Pivot()[i] of A.
+func (f *LU) Pivot() (p []int) {
+	p = make([]int, len(f.pivot))
+	copy(p, f.pivot)
+	return
+}
+
+// Det returns the determinant of the factorized matrix.
+func (f *LU) Det() float64 {
+	return luDet(f.lu.entries, f.lu.rows, f.sign)
+}
+
+// Solve returns the solution x of A * x = b.
+// Returns an error, if the sizes don't match or A is singular.
+func (f *LU) Solve(b *Vector) (x *Vector, e error) {
+	n := f.lu.rows
+	// check sizes
+	if b.Size() != n {
+		e = &DimensionError{Op: "Solve", A: f.lu.shape(), B: b.shape()}
+		return
+	}
+	if f.IsSingular() {
+		e = ErrSingular
+		return
+	}
+	// apply permutation
+	x = ZeroVec(n)
+	for i := 0; i < n; i++ {
+		x.entries[i] = b.at(f.pivot[i])
+	}
+	luSolveInPlace(f.lu.entries, n, x.entries)
+	return
+}
+
+// SolveMat returns the solution X of A * X = B,
+// i.e. it solves for every column of B.
+// Returns an error, if the sizes don't match or A is singular.
+func (f *LU) SolveMat(b *Matrix) (x *Matrix, e error) {
+	n := f.lu.rows
+	// check sizes
+	if b.rows != n {
+		e = &DimensionError{Op: "SolveMat", A: f.lu.shape(), B: b.shape()}
+		return
+	}
+	if f.IsSingular() {
+		e = ErrSingular
+		return
+	}
+	x, _ = ZeroMat(n, b.cols)
+	col := make([]float64, n)
+	for j := 0; j < b.cols; j++ {
+		// solve for permuted j-th column
+		for i := 0; i < n; i++ {
+			col[i] = b.getEntry(f.pivot[i], j)
+		}
+		luSolveInPlace(f.lu.entries, n, col)
+		for i := 0; i < n; i++ {
+			x.entries[i*b.cols+j] = col[i]
+		}
+	}
+	return
+}
+
+// Inverse returns the inverse of the factorized matrix.
+// Returns ErrSingular, if the matrix is singular.
+func (f *LU) Inverse() (inv *Matrix, e error) {
+	n := f.lu.rows
+	id, _ := IdMat(n, n)
+	inv, e = f.SolveMat(id)
+	return
+}
+
+// Det returns the determinant of the square matrix a.
+// Returns NaN if a is not square.
+func (a *Matrix) Det() float64 {
+	f, e := NewLU(a)
+	if e != nil {
+		return math.NaN()
+	}
+	return f.Det()
+}
+
+// Inverse returns the inverse of the square matrix a.
+// Returns an error, if a is not square or singular.
+func (a *Matrix) Inverse() (inv *Matrix, e error) {
+	f, e := NewLU(a)
+	if e != nil {
+		return
+	}
+	inv, e = f.Inverse()
+	return
+}
+
+// Solve returns the solution x of a * x = b for a square matrix a.
+// Use NewLU to solve for many right hand sides.
+func (a *Matrix) Solve(b *Vector) (x *Vector, e error) {
+	f, e := NewLU(a)
+	if e != nil {
+		return
+	}
+	x, e = f.Solve(b)
+	return
+}
+
+// luFactor computes the LU decomposition with partial pivoting of the n x n
+// matrix stored row by row in lu in place, for real and complex entries.
+// abs is the absolute value used to choose the pivots.
+// Returns the row permutation, its sign and the tolerance
+// below which pivots are treated as zero.
+func luFactor[T float64 | complex128](lu []T, n int, abs func(T) float64) (pivot []int, sign, tol float64) {
+	pivot = make([]int, n)
+	sign = 1
+	for i := range pivot {
+		pivot[i] = i
+	}
+	// pivots below tol are treated as zero
+	var maxAbs float64 = 0
+	for _, x := range lu {
+		maxAbs = math.Max(maxAbs, abs(x))
+	}
+	tol = float64(n) * maxAbs * eps
+	for k := 0; k < n; k++ {
+		// find pivot in column k
+		p := k
+		for i := k + 1; i < n; i++ {
+			if abs(lu[i*n+k]) > abs(lu[p*n+k]) {
+				p = i
+			}
+		}
+		// swap rows p and k
+		if p != k {
+			for j := 0; j < n; j++ {
+				lu[p*n+j], lu[k*n+j] = lu[k*n+j], lu[p*n+j]
+			}
+			pivot[p], pivot[k] = pivot[k], pivot[p]
+			sign = -sign
+		}
+		// eliminate entries below the pivot
+		pk := lu[k*n+k]
+		if pk == 0 {
+			continue
+		}
+		for i := k + 1; i < n; i++ {
+			lu[i*n+k] /= pk
+			lik := lu[i*n+k]
+			if lik == 0 {
+				continue
+			}
+			for j := k + 1; j < n; j++ {
+				lu[i*n+j] -= lik * lu[k*n+j]
+			}
+		}
+	}
+	return
+}
+
+// luSingular reports whether a pivot of the factors lu is below tol
+func luSingular[T float64 | complex128](lu []T, n int, tol float64, abs func(T) float64) bool {
+	for k := 0; k < n; k++ {
+		if abs(lu[k*n+k]) <= tol {
+			return true
+		}
+	}
+	return false
+}
+
+// luDet returns the determinant from the factors lu and the sign of the permutation
+func luDet[T float64 | complex128](lu []T, n int, sign float64) T {
+	var det T = 1
+	if sign < 0 {
+		det = -det
+	}
+	for k := 0; k < n; k++ {
+		det *= lu[k*n+k]
+	}
+	return det
+}
+
+// luSolveInPlace overwrites the permuted right hand side x
+// with the solution of L * U * x = x.
+func luSolveInPlace[T float64 | complex128](lu []T, n int, x []T) {
+	// forward substitution with L
+	for i := 1; i < n; i++ {
+		var sum T
+		for j := 0; j < i; j++ {
+			sum += lu[i*n+j] * x[j]
+		}
+		x[i] -= sum
+	}
+	// backward substitution with U
+	for i := n - 1; i >= 0; i-- {
+		sum := x[i]
+		for j := i + 1; j < n; j++ {
+			sum -= lu[i*n+j] * x[j]
+		}
+		x[i] = sum / lu[i*n+i]
+	}
+}
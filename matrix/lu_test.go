@@ -0,0 +1,68 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLU(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, n := range []int{1, 2, 6, 20} {
+		a := randMat(n, n, rng)
+		f, e := NewLU(a)
+		if e != nil {
+			t.Fatal(n, e)
+		}
+		// P * A = L * U
+		pa, _ := ZeroMat(n, n)
+		for i, p := range f.Pivot() {
+			pa.SetRow(i, a.GetRow(p))
+		}
+		matClose(t, "PA = LU", pa, f.L().Mul(f.U()), 1e-12)
+		b := randVec(n, rng)
+		x, e := f.Solve(b)
+		if e != nil {
+			t.Fatal(n, e)
+		}
+		vecClose(t, "Solve", a.MulVec(x), b, 1e-9)
+		inv, e := a.Inverse()
+		if e != nil {
+			t.Fatal(n, e)
+		}
+		id, _ := IdMat(n, n)
+		matClose(t, "Inverse", a.Mul(inv), id, 1e-9)
+	}
+}
+
+func TestDet(t *testing.T) {
+	tests := []struct {
+		name string
+		a    [][]float64
+		det  float64
+	}{
+		{"triangular", [][]float64{{2, 0}, {1, 3}}, 6},
+		{"permutation", [][]float64{{0, 1}, {1, 0}}, -1},
+		{"singular", [][]float64{{1, 2}, {2, 4}}, 0},
+		{"3x3", [][]float64{{2, -1, 0}, {-1, 2, -1}, {0, -1, 2}}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := MatrixFromSlice(tt.a)
+			if d := a.Det(); math.Abs(d-tt.det) > 1e-12 {
+				t.Fatal(d)
+			}
+		})
+	}
+}
+
+func TestLUSingular(t *testing.T) {
+	s, _ := MatrixFromSlice([][]float64{{1, 2}, {2, 4}})
+	if _, e := s.Inverse(); !errors.Is(e, ErrSingular) {
+		t.Fatal(e)
+	}
+	if _, e := s.Solve(VecFromSlice([]float64{1, 1})); !errors.Is(e, ErrSingular) {
+		t.Fatal(e)
+	}
+}
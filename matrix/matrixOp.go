@@ -19,6 +19,17 @@ func (m *Matrix) Cols() int {
 	return m.cols
 }
 
+// Dims returns the number of rows and cols of the matrix
+func (m *Matrix) Dims() (r, c int) {
+	return m.rows, m.cols
+}
+
+// At(i,j) returns the element in row i and column j like Get,
+// so that Matrix implements the Mat interface.
+func (m *Matrix) At(i, j int) float64 {
+	return m.Get(i, j)
+}
+
 // Get(i,j) returns the element in row i and column j.
 // returns NaN if invalid index
 func (m *Matrix) Get(i, j int) float64 {
@@ -36,16 +47,12 @@ func (m *Matrix) Set(i, j int, v float64) {
 	return
 }
 
-// GetSafe(i,j) returns the element in row i and column j and an error value
+// GetSafe(i,j) returns the element in row i and column j and an error value.
+// Returns an IndexError, if invalid indices
 func (m *Matrix) GetSafe(i, j int) (elem float64, e error) {
 	// check if valid indices
-	if i < 0 || j < 0 {
-		e = fmt.Errorf("Error: indices are negative")
-		return
-	}
-	// check if out of bounds
-	if i >= m.rows || j >= m.cols {
-		e = fmt.Errorf("Error: indices are out of bound")
+	if i < 0 || j < 0 || i >= m.rows || j >= m.cols {
+		e = &IndexError{Op: "Get", Index: []int{i, j}, Dims: m.shape()}
 		return
 	}
 	// return elem
@@ -53,16 +60,12 @@ func (m *Matrix) GetSafe(i, j int) (elem float64, e error) {
 	return
 }
 
-// SetSafe makes the same as safe, but it doesn't ignore the error values
+// SetSafe makes the same as safe, but it doesn't ignore the error values.
+// Returns an IndexError, if invalid indices
 func (m *Matrix) SetSafe(i, j int, v float64) (e error) {
 	// check if valid indices
-	if i < 0 || j < 0 {
-		e = fmt.Errorf("Error: indices are negative")
-		return
-	}
-	// check if out of bounds
-	if i >= m.rows || j >= m.cols {
-		e = fmt.Errorf("Error: indices are out of bound")
+	if i < 0 || j < 0 || i >= m.rows || j >= m.cols {
+		e = &IndexError{Op: "Set", Index: []int{i, j}, Dims: m.shape()}
 		return
 	}
 	// set entry
@@ -73,8 +76,16 @@ func (m *Matrix) SetSafe(i, j int, v float64) (e error) {
 // GetRow returns a new vector of the i-th row of matrix m.
 // Returns nil if invalid index
 func (m *Matrix) GetRow(i int) (v *Vector) {
+	v, _ = m.GetRowSafe(i)
+	return
+}
+
+// GetRowSafe returns a new vector of the i-th row of matrix m.
+// Returns an IndexError, if invalid index
+func (m *Matrix) GetRowSafe(i int) (v *Vector, e error) {
 	// check if valid idx
 	if i < 0 || i >= m.rows {
+		e = &IndexError{Op: "GetRow", Index: []int{i}, Dims: m.shape()}
 		return
 	}
 	// create vector
@@ -89,21 +100,42 @@ func (m *Matrix) GetRow(i int) (v *Vector) {
 // SetRow sets i-th row of matrix m.
 // Doesn't update, if invalid index or missmatching sizes,
 func (m *Matrix) SetRow(i int, vec *Vector) {
+	m.SetRowSafe(i, vec)
+}
+
+// SetRowSafe sets i-th row of matrix m.
+// Returns an IndexError or a DimensionError and doesn't update,
+// if invalid index or missmatching sizes
+func (m *Matrix) SetRowSafe(i int, vec *Vector) (e error) {
 	// check if valid i and vec
-	if i < 0 || i >= m.rows || vec.Size() != m.cols {
+	if i < 0 || i >= m.rows {
+		e = &IndexError{Op: "SetRow", Index: []int{i}, Dims: m.shape()}
+		return
+	}
+	if vec.Size() != m.cols {
+		e = &DimensionError{Op: "SetRow", A: m.shape(), B: vec.shape()}
 		return
 	}
 	// update i-th row
 	for j := 0; j < m.cols; j++ {
-		m.setEntry(i,j, vec.entries[j])
+		m.setEntry(i,j, vec.at(j))
 	}
+	return
 }
 
 // GetCol returns a new vector with the contents of j-th column.
 // Returns nil if invalid index
 func (m *Matrix) GetCol(j int) (v *Vector) {
+	v, _ = m.GetColSafe(j)
+	return
+}
+
+// GetColSafe returns a new vector with the contents of j-th column.
+// Returns an IndexError, if invalid index
+func (m *Matrix) GetColSafe(j int) (v *Vector, e error) {
 	// check if valid idx
 	if j < 0 || j >= m.cols {
+		e = &IndexError{Op: "GetCol", Index: []int{j}, Dims: m.shape()}
 		return
 	}
 	// return vector
@@ -117,23 +149,43 @@ func (m *Matrix) GetCol(j int) (v *Vector) {
 // SetRow sets j-th column of matrix m.
 // Doesn't update, if invalid index or missmatching sizes,
 func (m *Matrix) SetCol(j int, vec *Vector) {
+	m.SetColSafe(j, vec)
+}
+
+// SetColSafe sets j-th column of matrix m.
+// Returns an IndexError or a DimensionError and doesn't update,
+// if invalid index or missmatching sizes
+func (m *Matrix) SetColSafe(j int, vec *Vector) (e error) {
 	// check sizes
-	if j < 0 || j >= m.cols || vec.Size() != m.rows {
+	if j < 0 || j >= m.cols {
+		e = &IndexError{Op: "SetCol", Index: []int{j}, Dims: m.shape()}
+		return
+	}
+	if vec.Size() != m.rows {
+		e = &DimensionError{Op: "SetCol", A: m.shape(), B: vec.shape()}
 		return
 	}
 	// update j-th column
 	for i := 0; i < m.rows; i++ {
-		m.setEntry(i,j, vec.entries[i])
+		m.setEntry(i,j, vec.at(i))
 	}
+	return
 }
 
-// CopyMatrix returns a new matrix with the same contents
+// CopyMatrix returns a new matrix with the same contents.
+// The copy of a view doesn't share the storage anymore.
 func (a *Matrix) CopyMat() (m *Matrix) {
 	m, _ = ZeroMat(a.rows, a.cols)
 
 	// copy entries
-	for i := range a.entries {
-		m.entries[i] = a.entries[i]
+	if a.isContiguous() {
+		copy(m.entries, a.entries)
+		return
+	}
+	for i := 0; i < a.rows; i++ {
+		for j := 0; j < a.cols; j++ {
+			m.setEntry(i, j, a.getEntry(i, j))
+		}
 	}
 	return
 }
@@ -150,13 +202,18 @@ func (a *Matrix) CopyMat() (m *Matrix) {
 // The indices are always inclusive.
 // (like in GetSubVec and SetSubVec)
 func (a *Matrix) GetBlock(urow, ucol, lrow, lcol int) (b *Matrix) {
+	b, _ = a.GetBlockSafe(urow, ucol, lrow, lcol)
+	return
+}
+
+// GetBlockSafe returns a submatrix of a, like GetBlock.
+// Returns an IndexError, if invalid indices
+func (a *Matrix) GetBlockSafe(urow, ucol, lrow, lcol int) (b *Matrix, e error) {
 	// check range
 	if urow < 0 || urow >= a.Rows() || lrow < 0 || lrow >= a.Rows() ||
-		ucol < 0 || ucol >= a.Cols() || lcol < 0 || lcol >= a.Cols() {
-		return
-	}
-	// check
-	if urow > lrow || ucol > lcol {
+		ucol < 0 || ucol >= a.Cols() || lcol < 0 || lcol >= a.Cols() ||
+		urow > lrow || ucol > lcol {
+		e = &IndexError{Op: "GetBlock", Index: []int{urow, ucol, lrow, lcol}, Dims: a.shape()}
 		return
 	}
 	// define dimensions
@@ -177,16 +234,22 @@ func (a *Matrix) GetBlock(urow, ucol, lrow, lcol int) (b *Matrix) {
 // Computes c = a + b, if dimensions match.
 // Dimension mismatch returns nil.
 func (a *Matrix) Add(b *Matrix) (c *Matrix) {
+	c, _ = a.AddSafe(b)
+	return
+}
+
+// AddSafe computes c = a + b.
+// Returns a DimensionError, if dimensions don't match.
+func (a *Matrix) AddSafe(b *Matrix) (c *Matrix, e error) {
 	// check if dimensions match
 	if a.rows != b.rows || a.cols != b.cols {
+		e = &DimensionError{Op: "Add", A: a.shape(), B: b.shape()}
 		return
 	}
 	// create new matrix
 	c, _ = ZeroMat(a.rows, a.cols)
 	// iterate over matrices and add up
-	for i := range a.entries {
-		c.entries[i] = a.entries[i] + b.entries[i]
-	}
+	c.zipOf(a, b, kernel{op: opAdd}, Workers())
 	return
 }
 
@@ -194,50 +257,65 @@ func (a *Matrix) Add(b *Matrix) (c *Matrix) {
 // Computes c = a - b, if dimensions match.
 // Dimension mismatch returns nil
 func (a *Matrix) Sub(b *Matrix) (c *Matrix) {
+	c, _ = a.SubSafe(b)
+	return
+}
+
+// SubSafe computes c = a - b.
+// Returns a DimensionError, if dimensions don't match.
+func (a *Matrix) SubSafe(b *Matrix) (c *Matrix, e error) {
 	// check if dimensions match
 	if a.rows != b.rows || a.cols != b.cols {
+		e = &DimensionError{Op: "Sub", A: a.shape(), B: b.shape()}
 		return
 	}
 	// create new matrix
 	c, _ = ZeroMat(a.rows, a.cols)
 	// iterate over matrices and substract
-	for i := range a.entries {
-		c.entries[i] = a.entries[i] - b.entries[i]
-	}
+	c.zipOf(a, b, kernel{op: opSub}, Workers())
 	return
 }
 
 // Scale returns a scaled matrix by factor f.
 // Returns nil pointer if factor = inf, or NaN
 func (a *Matrix) Scale(factor float64) (c *Matrix) {
+	c, _ = a.ScaleSafe(factor)
+	return
+}
+
+// ScaleSafe returns a scaled matrix by factor f.
+// Returns ErrInvalidArgument, if factor = inf, or NaN
+func (a *Matrix) ScaleSafe(factor float64) (c *Matrix, e error) {
 	// check if factor is valid
-	if math.IsNaN(factor) {
-		return
-	}
-	if math.IsInf(factor, 1) {
-		return
-	}
-	if math.IsInf(factor, -1) {
+	if math.IsNaN(factor) || math.IsInf(factor, 0) {
+		e = fmt.Errorf("%w: scale factor %v", ErrInvalidArgument, factor)
 		return
 	}
 	// multiply matrix a by factor
-	c = a.ApplyFunc(func(x float64) float64 { return x * factor })
+	c, _ = ZeroMat(a.rows, a.cols)
+	c.zipOf(a, nil, kernel{op: opScale, alpha: factor}, Workers())
 	return
 }
 
 // CWiseProd computes the compnent-wise product of matrices a and b.
 // Dimension mismatch returns nil
 func (a *Matrix) CWiseProd(b *Matrix) (c *Matrix) {
+	c, _ = a.CWiseProdSafe(b)
+	return
+}
+
+// CWiseProdSafe computes the compnent-wise product of matrices a and b.
+// Returns a DimensionError, if dimensions don't match.
+func (a *Matrix) CWiseProdSafe(b *Matrix) (c *Matrix, e error) {
 	// check if sizes match
 	if a.rows != b.rows || a.cols != b.cols {
+		e = &DimensionError{Op: "CWiseProd", A: a.shape(), B: b.shape()}
 		return
 	}
 	// allocate new matrix
 	c, _ = ZeroMat(a.rows, a.cols)
 	// compute cwise product
-	for i := range a.entries {
-		c.entries[i] = a.entries[i] * b.entries[i]
-	}
+	c.zipOf(a, b, kernel{op: opCWiseProd}, Workers())
 	return
 }
 
@@ -246,8 +324,16 @@ func (a *Matrix) ApplyFunc(f func(float64) float64) (m *Matrix) {
 	// create new matrix
 	m, _ = ZeroMat(a.rows, a.cols)
 	// apply function on elements
-	for i := range a.entries {
-		m.entries[i] = f(a.entries[i])
+	if a.isContiguous() {
+		for i := range m.entries {
+			m.entries[i] = f(a.entries[i])
+		}
+		return
+	}
+	for i := 0; i < a.rows; i++ {
+		for j := 0; j < a.cols; j++ {
+			m.setEntry(i, j, f(a.getEntry(i, j)))
+		}
 	}
 	return
 }
@@ -255,11 +341,154 @@ func (a *Matrix) ApplyFunc(f func(float64) float64) (m *Matrix) {
 // getEntry returns the entry in the i row and j column.
 // not exported, intended to use as building block for library
 func (a *Matrix) getEntry(i,j int) float64 {
-	return a.entries[a.cols * i + j]
+	return a.entries[a.rstride*i+a.cstride*j]
 }
 
 // setEntry sets value v to the matrix element at row i and column j.
 // not exported, intended to use as building block for library
 func (a *Matrix) setEntry(i,j int, v float64) {
-	a.entries[a.cols * i + j] = v
+	a.entries[a.rstride*i+a.cstride*j] = v
+}
+
+// Mul computes the matrix product c = a * b.
+// Dimension mismatch (a.Cols() != b.Rows()) returns nil.
+func (a *Matrix) Mul(b *Matrix) (c *Matrix) {
+	c, _ = a.MulSafe(b)
+	return
+}
+
+// MulSafe computes the matrix product c = a * b.
+// Returns a DimensionError, if a.Cols() != b.Rows().
+func (a *Matrix) MulSafe(b *Matrix) (c *Matrix, e error) {
+	// check if dimensions match
+	if a.cols != b.rows {
+		e = &DimensionError{Op: "Mul", A: a.shape(), B: b.shape()}
+		return
+	}
+	// create new matrix
+	c, _ = ZeroMat(a.rows, b.cols)
+	c.mulAdd(a, b)
+	return
+}
+
+// mulAdd computes c = c + a * b with the package-level number of workers.
+// c needs cstride = 1 and must not share storage with a or b.
+func (c *Matrix) mulAdd(a, b *Matrix) {
+	c.mulAddN(a, b, Workers())
+}
+
+// TMul computes the matrix product c = a^T * b without forming a^T.
+// Dimension mismatch (a.Rows() != b.Rows()) returns nil.
+func (a *Matrix) TMul(b *Matrix) (c *Matrix) {
+	c, _ = a.TMulSafe(b)
+	return
+}
+
+// TMulSafe computes the matrix product c = a^T * b without forming a^T.
+// Returns a DimensionError, if a.Rows() != b.Rows().
+func (a *Matrix) TMulSafe(b *Matrix) (c *Matrix, e error) {
+	// check if dimensions match
+	if a.rows != b.rows {
+		e = &DimensionError{Op: "TMul", A: a.shape(), B: b.shape()}
+		return
+	}
+	// create new matrix
+	c, _ = ZeroMat(a.cols, b.cols)
+	// multiply with the transpose view of a
+	c.mulAdd(a.T(), b)
+	return
+}
+
+// MulT computes the matrix product c = a * b^T without forming b^T.
+// Dimension mismatch (a.Cols() != b.Cols()) returns nil.
+func (a *Matrix) MulT(b *Matrix) (c *Matrix) {
+	c, _ = a.MulTSafe(b)
+	return
+}
+
+// MulTSafe computes the matrix product c = a * b^T without forming b^T.
+// Returns a DimensionError, if a.Cols() != b.Cols().
+func (a *Matrix) MulTSafe(b *Matrix) (c *Matrix, e error) {
+	// check if dimensions match
+	if a.cols != b.cols {
+		e = &DimensionError{Op: "MulT", A: a.shape(), B: b.shape()}
+		return
+	}
+	// create new matrix
+	c, _ = ZeroMat(a.rows, b.rows)
+	// the inner loop needs the rows of a and b as slices
+	if a.cstride != 1 {
+		a = a.CopyMat()
+	}
+	if b.cstride != 1 {
+		b = b.CopyMat()
+	}
+	// every entry is a dot product of a row of a and a row of b
+	work := a.rows * a.cols * b.rows
+	if chunks := min(numChunks(work, Workers(), minParallelWork), a.rows); chunks > 1 {
+		c.mulTRowsParallel(a, b, chunks)
+		return
+	}
+	c.mulTRows(a, b, 0, a.rows)
+	return
+}
+
+// MulVec computes the matrix-vector product w = a * v.
+// Dimension mismatch (a.Cols() != v.Size()) returns nil.
+func (a *Matrix) MulVec(v *Vector) (w *Vector) {
+	w, _ = a.MulVecSafe(v)
+	return
+}
+
+// MulVecSafe computes the matrix-vector product w = a * v.
+// Returns a DimensionError, if a.Cols() != v.Size().
+func (a *Matrix) MulVecSafe(v *Vector) (w *Vector, e error) {
+	// check if dimensions match
+	if a.cols != v.Size() {
+		e = &DimensionError{Op: "MulVec", A: a.shape(), B: v.shape()}
+		return
+	}
+	// create new vector
+	w = ZeroVec(a.rows)
+	if chunks := min(numChunks(a.rows*a.cols, Workers(), minParallelWork), a.rows); chunks > 1 {
+		w.mulVecRowsParallel(a, v, chunks)
+		return
+	}
+	w.mulVecRows(a, v, 0, a.rows)
+	return
+}
+
+// TMulVec computes the matrix-vector product w = a^T * v without forming a^T.
+// Dimension mismatch (a.Rows() != v.Size()) returns nil.
+func (a *Matrix) TMulVec(v *Vector) (w *Vector) {
+	w, _ = a.TMulVecSafe(v)
+	return
+}
+
+// TMulVecSafe computes the matrix-vector product w = a^T * v without forming a^T.
+// Returns a DimensionError, if a.Rows() != v.Size().
+func (a *Matrix) TMulVecSafe(v *Vector) (w *Vector, e error) {
+	// check if dimensions match
+	if a.rows != v.Size() {
+		e = &DimensionError{Op: "TMulVec", A: a.shape(), B: v.shape()}
+		return
+	}
+	// create new vector
+	w = ZeroVec(a.cols)
+	for i := 0; i < a.rows; i++ {
+		vi := v.at(i)
+		for j := 0; j < a.cols; j++ {
+			w.entries[j] += vi * a.getEntry(i, j)
+		}
+	}
+	return
+}
+
+// Diag returns a copy of the main diagonal of a.
+func (a *Matrix) Diag() (d *Vector) {
+	d = ZeroVec(min(a.rows, a.cols))
+	for i := range d.entries {
+		d.entries[i] = a.getEntry(i, i)
+	}
+	return
 }
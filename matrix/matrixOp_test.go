@@ -0,0 +1,69 @@
+package matrix
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestProducts(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name    string
+		m, k, n int
+	}{
+		{"square", 4, 4, 4},
+		{"wide", 2, 5, 3},
+		{"tall", 6, 3, 2},
+		{"vector", 1, 4, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := randMat(tt.m, tt.k, rng)
+			b := randMat(tt.k, tt.n, rng)
+			matClose(t, "Mul", a.Mul(b), naiveMul(a, b), 1e-12)
+			c := randMat(tt.m, tt.n, rng)
+			matClose(t, "TMul", a.TMul(c), naiveMul(naiveT(a), c), 1e-12)
+			d := randMat(tt.n, tt.k, rng)
+			matClose(t, "MulT", a.MulT(d), naiveMul(a, naiveT(d)), 1e-12)
+			v := randVec(tt.k, rng)
+			vecClose(t, "MulVec", a.MulVec(v), naiveMul(a, v.Mat()).GetCol(0), 1e-12)
+			w := randVec(tt.m, rng)
+			want := naiveMul(naiveT(a), w.Mat()).GetCol(0)
+			vecClose(t, "TMulVec", a.TMulVec(w), want, 1e-12)
+			vecClose(t, "MulMat", w.MulMat(a), want, 1e-12)
+		})
+	}
+}
+
+func TestProductsDimensionMismatch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	a := randMat(4, 3, rng)
+	b := randMat(3, 5, rng)
+	if a.Mul(a) != nil || a.TMul(b) != nil || a.MulT(b) != nil {
+		t.Fatal("mismatched products should be nil")
+	}
+	if a.MulVec(randVec(4, rng)) != nil || a.TMulVec(randVec(3, rng)) != nil {
+		t.Fatal("mismatched matrix-vector products should be nil")
+	}
+}
+
+func TestTMulVecNaN(t *testing.T) {
+	tests := []struct {
+		name string
+		x    float64
+	}{
+		{"NaN", math.NaN()},
+		{"Inf", math.Inf(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the zero element of v meets the NaN or Inf of a
+			a, _ := MatrixFromSlice([][]float64{{tt.x, 1}, {2, 3}})
+			w := a.TMulVec(VecFromSlice([]float64{0, 1}))
+			if !math.IsNaN(w.Get(0)) || w.Get(1) != 3 {
+				t.Fatal(w.Get(0), w.Get(1))
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+/*	This file implements reading and writing of the Matrix Market exchange
+	format (https://math.nist.gov/MatrixMarket/formats.html)
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxMMEntries limits the number of entries of a dense matrix read in
+// Matrix Market format (16 GiB)
+const maxMMEntries = 1 << 31
+
+// ReadMatrixMarket reads a matrix in Matrix Market format from r.
+// Supported are the coordinate and array formats with real, integer or
+// pattern (entries equal to 1) fields and general, symmetric or
+// skew-symmetric structure. Coordinate matrices are returned dense,
+// use ReadMatrixMarketSparse to keep them sparse.
+// Returns an error wrapping ErrFormat, if the input is malformed or
+// the matrix has more than 2^31 entries (16 GiB).
+func ReadMatrixMarket(r io.Reader) (m *Matrix, e error) {
+	c, e := ReadMatrixMarketSparse(r)
+	if e != nil {
+		return
+	}
+	// the dimensions are untrusted, check the size before densifying
+	if c.rows > maxMMEntries/c.cols {
+		e = fmt.Errorf("%w: matrix market: %dx%d matrix exceeds %d entries", ErrFormat, c.rows, c.cols, maxMMEntries)
+		return
+	}
+	m = c.ToDense()
+	return
+}
+
+// ReadMatrixMarketSparse reads a matrix in Matrix Market format from r
+// like ReadMatrixMarket, but returns it in coordinate format.
+// Zero entries of the array format are not stored.
+func ReadMatrixMarketSparse(r io.Reader) (c *COO, e error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	// parse banner
+	if !sc.Scan() {
+		e = mmError(line, "missing header")
+		if err := sc.Err(); err != nil {
+			e = err
+		}
+		return
+	}
+	line++
+	banner := strings.Fields(strings.ToLower(sc.Text()))
+	if len(banner) != 5 || banner[0] != "%%matrixmarket" || banner[1] != "matrix" {
+		e = mmError(line, "invalid header %q", sc.Text())
+		return
+	}
+	format, field, symmetry := banner[2], banner[3], banner[4]
+	if format != "coordinate" && format != "array" {
+		e = mmError(line, "unsupported format %q", format)
+		return
+	}
+	if field != "real" && field != "integer" && (field != "pattern" || format != "coordinate") {
+		e = mmError(line, "unsupported field %q for %s format", field, format)
+		return
+	}
+	if symmetry != "general" && symmetry != "symmetric" && symmetry != "skew-symmetric" {
+		e = mmError(line, "unsupported symmetry %q", symmetry)
+		return
+	}
+	// next returns the fields of the next line, which isn't a comment
+	next := func() (fields []string, ok bool) {
+		for sc.Scan() {
+			line++
+			text := strings.TrimSpace(sc.Text())
+			if text == "" || text[0] == '%' {
+				continue
+			}
+			return strings.Fields(text), true
+		}
+		return nil, false
+	}
+	// parse size line
+	fields, ok := next()
+	nSize := 3
+	if format == "array" {
+		nSize = 2
+	}
+	if !ok || len(fields) != nSize {
+		e = mmError(line, "invalid size line")
+		return
+	}
+	size := make([]int, nSize)
+	for k, f := range fields {
+		if size[k], e = strconv.Atoi(f); e != nil || size[k] < 0 {
+			e = mmError(line, "invalid size %q", f)
+			return
+		}
+	}
+	rows, cols := size[0], size[1]
+	if symmetry != "general" && rows != cols {
+		e = mmError(line, "%s matrix must be square", symmetry)
+		return
+	}
+	m, e := NewCOO(rows, cols)
+	if e != nil {
+		e = mmError(line, "invalid dimensions %dx%d", rows, cols)
+		return
+	}
+	// add stores an entry and its mirrored counterpart
+	add := func(i, j int, v float64) {
+		m.Append(i, j, v)
+		if i != j && symmetry == "symmetric" {
+			m.Append(j, i, v)
+		} else if i != j && symmetry == "skew-symmetric" {
+			m.Append(j, i, -v)
+		}
+	}
+	if format == "coordinate" {
+		nValues := 3
+		if field == "pattern" {
+			nValues = 2
+		}
+		for k := 0; k < size[2]; k++ {
+			fields, ok = next()
+			if !ok || len(fields) != nValues {
+				e = mmError(line, "expected %d entries, got %d", size[2], k)
+				return
+			}
+			i, err1 := strconv.Atoi(fields[0])
+			j, err2 := strconv.Atoi(fields[1])
+			if err1 != nil || err2 != nil || i < 1 || i > rows || j < 1 || j > cols {
+				e = mmError(line, "invalid index (%s, %s)", fields[0], fields[1])
+				return
+			}
+			var v float64 = 1
+			if field != "pattern" {
+				if v, e = mmValue(line, fields[2]); e != nil {
+					return
+				}
+			}
+			if symmetry != "general" && j > i {
+				e = mmError(line, "entry (%d, %d) above the diagonal of a %s matrix", i, j, symmetry)
+				return
+			}
+			add(i-1, j-1, v)
+		}
+	} else {
+		// column-major, only the lower triangle for symmetric matrices
+		for j := 0; j < cols; j++ {
+			start := 0
+			if symmetry == "symmetric" {
+				start = j
+			} else if symmetry == "skew-symmetric" {
+				start = j + 1
+			}
+			for i := start; i < rows; i++ {
+				fields, ok = next()
+				if !ok || len(fields) != 1 {
+					e = mmError(line, "missing value of entry (%d, %d)", i+1, j+1)
+					return
+				}
+				v, err := mmValue(line, fields[0])
+				if err != nil {
+					e = err
+					return
+				}
+				if v != 0 {
+					add(i, j, v)
+				}
+			}
+		}
+	}
+	if e = sc.Err(); e != nil {
+		return
+	}
+	c = m
+	return
+}
+
+// ReadMatrixMarketVec reads a vector stored as a n x 1 or 1 x n matrix
+// in Matrix Market format from r.
+func ReadMatrixMarketVec(r io.Reader) (v *Vector, e error) {
+	m, e := ReadMatrixMarket(r)
+	if e != nil {
+		return
+	}
+	if m.rows != 1 && m.cols != 1 {
+		e = fmt.Errorf("%w: matrix market: %dx%d matrix is not a vector", ErrFormat, m.rows, m.cols)
+		return
+	}
+	v = VecFromSlice(m.entries)
+	return
+}
+
+// WriteMatrixMarket writes the matrix a in Matrix Market array format to w.
+// The structure is always general, use WriteMatrixMarketSym to write
+// only the lower triangle of a symmetric matrix.
+func WriteMatrixMarket(w io.Writer, a Mat) (e error) {
+	e = writeMatrixMarket(w, a, false)
+	return
+}
+
+// WriteMatrixMarketSym writes the lower triangle of the symmetric matrix a
+// in Matrix Market array format with symmetric structure to w.
+// Returns ErrNotSquare or ErrInvalidArgument, if a is not square or not
+// exactly symmetric, nothing is written then.
+func WriteMatrixMarketSym(w io.Writer, a Mat) (e error) {
+	if e = mmCheckSym(a); e != nil {
+		return
+	}
+	e = writeMatrixMarket(w, a, true)
+	return
+}
+
+// writeMatrixMarket writes a in array format, only the lower triangle if sym
+func writeMatrixMarket(w io.Writer, a Mat, sym bool) (e error) {
+	bw := bufio.NewWriter(w)
+	r, c := a.Dims()
+	fmt.Fprintf(bw, "%%%%MatrixMarket matrix array real %s\n%d %d\n", mmSymmetry(sym), r, c)
+	// column-major
+	for j := 0; j < c; j++ {
+		start := 0
+		if sym {
+			start = j
+		}
+		for i := start; i < r; i++ {
+			bw.WriteString(strconv.FormatFloat(a.At(i, j), 'g', -1, 64))
+			bw.WriteByte('\n')
+		}
+	}
+	e = bw.Flush()
+	return
+}
+
+// WriteMatrixMarketSparse writes the non-zero entries of a in Matrix Market
+// coordinate format to w. Sparse matrices are written without visiting
+// their zero entries. The structure is always general, use
+// WriteMatrixMarketSparseSym to write only the lower triangle of a
+// symmetric matrix.
+func WriteMatrixMarketSparse(w io.Writer, a Mat) (e error) {
+	e = writeMatrixMarketSparse(w, mmCSR(a), false)
+	return
+}
+
+// WriteMatrixMarketSparseSym writes the non-zero entries of the lower
+// triangle of the symmetric matrix a in Matrix Market coordinate format
+// with symmetric structure to w.
+// Returns ErrNotSquare or ErrInvalidArgument, if a is not square or not
+// exactly symmetric, nothing is written then.
+func WriteMatrixMarketSparseSym(w io.Writer, a Mat) (e error) {
+	s := mmCSR(a)
+	if e = mmCheckSymSparse(s); e != nil {
+		return
+	}
+	e = writeMatrixMarketSparse(w, s, true)
+	return
+}
+
+// writeMatrixMarketSparse writes s in coordinate format,
+// only the lower triangle if sym
+func writeMatrixMarketSparse(w io.Writer, s *CSR, sym bool) (e error) {
+	// keep reports whether the stored entry p in row i is written,
+	// explicitly stored zeros are skipped
+	keep := func(i, p int) bool {
+		return s.values[p] != 0 && (!sym || s.indices[p] <= i)
+	}
+	nnz := 0
+	for i := 0; i < s.major; i++ {
+		for p := s.indptr[i]; p < s.indptr[i+1]; p++ {
+			if keep(i, p) {
+				nnz++
+			}
+		}
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%%%%MatrixMarket matrix coordinate real %s\n%d %d %d\n", mmSymmetry(sym), s.major, s.minor, nnz)
+	for i := 0; i < s.major; i++ {
+		for p := s.indptr[i]; p < s.indptr[i+1]; p++ {
+			if !keep(i, p) {
+				continue
+			}
+			fmt.Fprintf(bw, "%d %d %s\n", i+1, s.indices[p]+1, strconv.FormatFloat(s.values[p], 'g', -1, 64))
+		}
+	}
+	e = bw.Flush()
+	return
+}
+
+// WriteMatrixMarketVec writes the vector v as n x 1 matrix in
+// Matrix Market array format to w.
+func WriteMatrixMarketVec(w io.Writer, v *Vector) (e error) {
+	e = WriteMatrixMarket(w, v.Mat())
+	return
+}
+
+// mmCSR returns a in compressed sparse row format
+func mmCSR(a Mat) (s *CSR) {
+	switch t := a.(type) {
+	case *CSR:
+		s = t
+	case *CSC:
+		s = t.ToCSR()
+	case *COO:
+		s = t.ToCSR()
+	default:
+		s = CSRFromDense(asMatrix(a))
+	}
+	return
+}
+
+// mmCheckSym returns an error, if a is not square or not exactly symmetric
+func mmCheckSym(a Mat) (e error) {
+	r, c := a.Dims()
+	if r != c {
+		e = ErrNotSquare
+		return
+	}
+	for i := 0; i < r; i++ {
+		for j := 0; j < i; j++ {
+			if a.At(i, j) != a.At(j, i) {
+				e = fmt.Errorf("%w: entries (%d, %d) and (%d, %d) differ", ErrInvalidArgument, i+1, j+1, j+1, i+1)
+				return
+			}
+		}
+	}
+	return
+}
+
+// mmCheckSymSparse returns an error, if s is not square or not exactly
+// symmetric, only the stored entries are visited
+func mmCheckSymSparse(s *CSR) (e error) {
+	if s.major != s.minor {
+		e = ErrNotSquare
+		return
+	}
+	for i := 0; i < s.major; i++ {
+		for p := s.indptr[i]; p < s.indptr[i+1]; p++ {
+			if j := s.indices[p]; s.values[p] != s.At(j, i) {
+				e = fmt.Errorf("%w: entries (%d, %d) and (%d, %d) differ", ErrInvalidArgument, i+1, j+1, j+1, i+1)
+				return
+			}
+		}
+	}
+	return
+}
+
+// mmSymmetry returns the symmetry of the header
+func mmSymmetry(sym bool) string {
+	if sym {
+		return "symmetric"
+	}
+	return "general"
+}
+
+// mmValue parses a real or integer value
+func mmValue(line int, s string) (v float64, e error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		e = mmError(line, "invalid value %q", s)
+	}
+	return
+}
+
+// mmError returns an error wrapping ErrFormat for the given line
+func mmError(line int, format string, args ...any) error {
+	return fmt.Errorf("%w: matrix market line %d: %s", ErrFormat, line, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,149 @@
+package matrix
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestMatrixMarketRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(17))
+	a := randMat(4, 3, rng)
+	a.Set(1, 1, 0)
+	sym, _ := MatrixFromSlice([][]float64{{4, 1, 0}, {1, 5, 2}, {0, 2, 6}})
+	tests := []struct {
+		name  string
+		m     *Matrix
+		write func(*bytes.Buffer, *Matrix) error
+	}{
+		{"array", a, func(b *bytes.Buffer, m *Matrix) error { return WriteMatrixMarket(b, m) }},
+		{"coordinate", a, func(b *bytes.Buffer, m *Matrix) error { return WriteMatrixMarketSparse(b, CSRFromDense(m)) }},
+		{"coordinate view", a.T(), func(b *bytes.Buffer, m *Matrix) error { return WriteMatrixMarketSparse(b, m) }},
+		{"array symmetric", sym, func(b *bytes.Buffer, m *Matrix) error { return WriteMatrixMarketSym(b, m) }},
+		{"coordinate symmetric", sym, func(b *bytes.Buffer, m *Matrix) error { return WriteMatrixMarketSparseSym(b, CSRFromDense(m)) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if e := tt.write(&buf, tt.m); e != nil {
+				t.Fatal(e)
+			}
+			b, e := ReadMatrixMarket(&buf)
+			if e != nil {
+				t.Fatal(e)
+			}
+			matClose(t, tt.name, b, tt.m, 0)
+		})
+	}
+	var buf bytes.Buffer
+	WriteMatrixMarketSparse(&buf, a)
+	c, e := ReadMatrixMarketSparse(&buf)
+	if e != nil || c.NNZ() != 11 {
+		t.Fatal(e)
+	}
+	matClose(t, "ReadMatrixMarketSparse", c.ToDense(), a, 0)
+	v := randVec(5, rng)
+	buf.Reset()
+	WriteMatrixMarketVec(&buf, v)
+	w, e := ReadMatrixMarketVec(&buf)
+	if e != nil {
+		t.Fatal(e)
+	}
+	vecClose(t, "vector", w, v, 0)
+}
+
+func TestMatrixMarketHeaders(t *testing.T) {
+	a, _ := MatrixFromSlice([][]float64{{4, 1, 0}, {1, 5, 2}, {0, 2, 7}})
+	tests := []struct {
+		name, prefix string
+		write        func(*bytes.Buffer) error
+	}{
+		{"general", "%%MatrixMarket matrix coordinate real general\n3 3 7\n", func(b *bytes.Buffer) error { return WriteMatrixMarketSparse(b, a) }},
+		{"symmetric", "%%MatrixMarket matrix coordinate real symmetric\n3 3 5\n", func(b *bytes.Buffer) error { return WriteMatrixMarketSparseSym(b, a) }},
+		{"array symmetric", "%%MatrixMarket matrix array real symmetric\n3 3\n", func(b *bytes.Buffer) error { return WriteMatrixMarketSym(b, a) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if e := tt.write(&buf); e != nil {
+				t.Fatal(e)
+			}
+			if !strings.HasPrefix(buf.String(), tt.prefix) {
+				t.Fatal(buf.String())
+			}
+		})
+	}
+}
+
+func TestMatrixMarketSymmetricErrors(t *testing.T) {
+	a, _ := MatrixFromSlice([][]float64{{4, 1, 7}, {1, 5, 2}, {0, 2, 6}})
+	var buf bytes.Buffer
+	if e := WriteMatrixMarketSym(&buf, a); !errors.Is(e, ErrInvalidArgument) || buf.Len() != 0 {
+		t.Fatal(e)
+	}
+	if e := WriteMatrixMarketSparseSym(&buf, a); !errors.Is(e, ErrInvalidArgument) || buf.Len() != 0 {
+		t.Fatal(e)
+	}
+	r, _ := ZeroMat(2, 3)
+	if e := WriteMatrixMarketSym(&buf, r); e != ErrNotSquare {
+		t.Fatal(e)
+	}
+	if e := WriteMatrixMarketSparseSym(&buf, r); e != ErrNotSquare {
+		t.Fatal(e)
+	}
+}
+
+func TestReadMatrixMarketFormats(t *testing.T) {
+	tests := []struct {
+		name, in string
+		want     [][]float64
+	}{
+		{"symmetric integer", "%%MatrixMarket matrix coordinate integer symmetric\n% comment\n3 3 4\n1 1 2\n2 1 -1\n3 2 5\n\n3 3 7\n",
+			[][]float64{{2, -1, 0}, {-1, 0, 5}, {0, 5, 7}}},
+		{"pattern", "%%MatrixMarket matrix coordinate pattern general\n2 2 2\n1 2\n2 1\n",
+			[][]float64{{0, 1}, {1, 0}}},
+		{"array skew-symmetric", "%%MatrixMarket matrix array real skew-symmetric\n3 3\n1\n2\n3\n",
+			[][]float64{{0, -1, -2}, {1, 0, -3}, {2, 3, 0}}},
+		{"array symmetric", "%%MatrixMarket matrix array real symmetric\n2 2\n1\n2\n3\n",
+			[][]float64{{1, 2}, {2, 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, e := ReadMatrixMarket(strings.NewReader(tt.in))
+			if e != nil {
+				t.Fatal(e)
+			}
+			want, _ := MatrixFromSlice(tt.want)
+			matClose(t, tt.name, m, want, 0)
+		})
+	}
+}
+
+func TestReadMatrixMarketErrors(t *testing.T) {
+	tests := []struct {
+		name, in string
+	}{
+		{"empty", ""},
+		{"complex", "%%MatrixMarket matrix coordinate complex general\n1 1 1\n1 1 1 1\n"},
+		{"truncated", "%%MatrixMarket matrix coordinate real general\n2 2 2\n1 1 1\n"},
+		{"index", "%%MatrixMarket matrix coordinate real general\n2 2 1\n3 1 1\n"},
+		{"number", "%%MatrixMarket matrix array real general\n1 1\nx\n"},
+		{"huge coordinate", "%%MatrixMarket matrix coordinate real general\n1000000000 1000000000 0\n"},
+		{"overflow", "%%MatrixMarket matrix coordinate real general\n9223372036854775807 9223372036854775807 0\n"},
+		{"huge array", "%%MatrixMarket matrix array real general\n1000000000 1000000000\n1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, e := ReadMatrixMarket(strings.NewReader(tt.in)); !errors.Is(e, ErrFormat) {
+				t.Fatal(e)
+			}
+		})
+	}
+	// the sparse reader doesn't densify
+	c, e := ReadMatrixMarketSparse(strings.NewReader("%%MatrixMarket matrix coordinate real general\n1000000000 1000000000 1\n5 7 2.5\n"))
+	if e != nil || c.At(4, 6) != 2.5 {
+		t.Fatal(e)
+	}
+}
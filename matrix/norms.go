@@ -0,0 +1,222 @@
+/*	This file implements norms of vectors and matrices and distances
+	between vectors
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// Norm1 returns the L1 norm sum |a_i|
+func (a *Vector) Norm1() float64 {
+	var result float64 = 0
+	for i := 0; i < a.Size(); i++ {
+		result += math.Abs(a.at(i))
+	}
+	return result
+}
+
+// Norm2 returns the euclidean norm sqrt(sum a_i^2).
+// The sum is scaled to avoid overflow and underflow.
+func (a *Vector) Norm2() float64 {
+	var scale float64 = 0
+	var ssq float64 = 1
+	inf := false
+	for i := 0; i < a.Size(); i++ {
+		x := math.Abs(a.at(i))
+		if x == 0 {
+			continue
+		}
+		if math.IsNaN(x) {
+			return math.NaN()
+		}
+		// Inf / Inf would be NaN, check the rest for NaN only
+		if math.IsInf(x, 1) {
+			inf = true
+		}
+		if inf {
+			continue
+		}
+		// sum of squares of a_i / scale
+		if scale < x {
+			ssq = 1 + ssq*(scale/x)*(scale/x)
+			scale = x
+		} else {
+			ssq += (x / scale) * (x / scale)
+		}
+	}
+	if inf {
+		return math.Inf(1)
+	}
+	return scale * math.Sqrt(ssq)
+}
+
+// NormInf returns the maximum norm max |a_i|
+func (a *Vector) NormInf() float64 {
+	var result float64 = 0
+	for i := 0; i < a.Size(); i++ {
+		x := math.Abs(a.at(i))
+		if math.IsNaN(x) {
+			return math.NaN()
+		}
+		result = math.Max(result, x)
+	}
+	return result
+}
+
+// Norm returns the p-norm (sum |a_i|^p)^(1/p) for p >= 1,
+// p = +Inf returns the maximum norm.
+// returns NaN if p < 1
+func (a *Vector) Norm(p float64) float64 {
+	switch {
+	case p == 1:
+		return a.Norm1()
+	case p == 2:
+		return a.Norm2()
+	case math.IsInf(p, 1):
+		return a.NormInf()
+	case !(p >= 1):
+		return math.NaN()
+	}
+	// scale by the maximum to avoid overflow
+	scale := a.NormInf()
+	if scale == 0 || math.IsInf(scale, 1) || math.IsNaN(scale) {
+		return scale
+	}
+	var sum float64 = 0
+	for i := 0; i < a.Size(); i++ {
+		sum += math.Pow(math.Abs(a.at(i))/scale, p)
+	}
+	return scale * math.Pow(sum, 1/p)
+}
+
+// NormFrob returns the Frobenius norm sqrt(sum a_ij^2)
+func (a *Matrix) NormFrob() float64 {
+	var scale float64 = 0
+	var ssq float64 = 1
+	inf := false
+	for i := 0; i < a.rows; i++ {
+		// combine the scaled sums of squares of the rows
+		r := a.RowView(i).Norm2()
+		if math.IsNaN(r) {
+			return math.NaN()
+		}
+		if math.IsInf(r, 1) {
+			inf = true
+		}
+		if r == 0 || inf {
+			continue
+		}
+		if scale < r {
+			ssq = 1 + ssq*(scale/r)*(scale/r)
+			scale = r
+		} else {
+			ssq += (r / scale) * (r / scale)
+		}
+	}
+	if inf {
+		return math.Inf(1)
+	}
+	return scale * math.Sqrt(ssq)
+}
+
+// Norm1 returns the maximum absolute column sum
+func (a *Matrix) Norm1() float64 {
+	var result float64 = 0
+	for j := 0; j < a.cols; j++ {
+		result = math.Max(result, a.ColView(j).Norm1())
+	}
+	return result
+}
+
+// NormInf returns the maximum absolute row sum
+func (a *Matrix) NormInf() float64 {
+	var result float64 = 0
+	for i := 0; i < a.rows; i++ {
+		result = math.Max(result, a.RowView(i).Norm1())
+	}
+	return result
+}
+
+// Norm2 returns the spectral norm, i.e. the largest singular value.
+// It needs a singular value decomposition, returns NaN if it fails.
+func (a *Matrix) Norm2() float64 {
+	f, e := NewSVD(a, false)
+	if e != nil {
+		return math.NaN()
+	}
+	return f.values.at(0)
+}
+
+// EuclideanDist returns the euclidean distance ||a - b||_2.
+// Dimension mismatch returns NaN.
+func (a *Vector) EuclideanDist(b *Vector) float64 {
+	return a.MinkowskiDist(b, 2)
+}
+
+// ManhattanDist returns the Manhattan distance ||a - b||_1.
+// Dimension mismatch returns NaN.
+func (a *Vector) ManhattanDist(b *Vector) float64 {
+	return a.MinkowskiDist(b, 1)
+}
+
+// ChebyshevDist returns the Chebyshev distance ||a - b||_inf.
+// Dimension mismatch returns NaN.
+func (a *Vector) ChebyshevDist(b *Vector) float64 {
+	return a.MinkowskiDist(b, math.Inf(1))
+}
+
+// MinkowskiDist returns the Minkowski distance ||a - b||_p for p >= 1.
+// Dimension mismatch or p < 1 returns NaN.
+func (a *Vector) MinkowskiDist(b *Vector, p float64) float64 {
+	d, e := a.MinkowskiDistSafe(b, p)
+	if e != nil {
+		return math.NaN()
+	}
+	return d
+}
+
+// MinkowskiDistSafe returns the Minkowski distance ||a - b||_p for p >= 1.
+// Returns a DimensionError, if sizes mismatch,
+// and ErrInvalidArgument, if p < 1.
+func (a *Vector) MinkowskiDistSafe(b *Vector, p float64) (d float64, e error) {
+	// check arguments
+	if !(p >= 1) {
+		e = fmt.Errorf("%w: norm order %v", ErrInvalidArgument, p)
+		return
+	}
+	diff, e := a.SubSafe(b)
+	if e != nil {
+		return
+	}
+	d = diff.Norm(p)
+	return
+}
+
+// CosineSimilarity returns a * b / (||a|| * ||b||).
+// Dimension mismatch or a zero vector returns NaN.
+func (a *Vector) CosineSimilarity(b *Vector) float64 {
+	return a.Dot(b) / (a.Norm2() * b.Norm2())
+}
+
+// CosineDist returns the cosine distance 1 - CosineSimilarity(a, b).
+// Dimension mismatch or a zero vector returns NaN.
+func (a *Vector) CosineDist(b *Vector) float64 {
+	return 1 - a.CosineSimilarity(b)
+}
+
+// MahalanobisDist returns the Mahalanobis distance
+// sqrt((a-b)^T * cov^-1 * (a-b)) for the covariance matrix cov.
+// Returns an error, if the sizes don't match or cov isn't positive definite.
+// Use Cholesky.Mahalanobis for many distances with the same covariance.
+func (a *Vector) MahalanobisDist(b *Vector, cov Mat) (d float64, e error) {
+	f, e := NewCholesky(cov)
+	if e != nil {
+		return
+	}
+	d, e = f.Mahalanobis(a, b)
+	return
+}
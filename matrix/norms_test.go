@@ -0,0 +1,117 @@
+package matrix
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestVectorNorms(t *testing.T) {
+	inf := math.Inf(1)
+	v := VecFromSlice([]float64{3, -4, 0})
+	big := VecFromSlice([]float64{1e200, 1e200})
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"Norm1", v.Norm1(), 7},
+		{"Norm2", v.Norm2(), 5},
+		{"NormInf", v.NormInf(), 4},
+		{"Norm(3)", v.Norm(3), math.Cbrt(27 + 64)},
+		{"Norm(Inf)", v.Norm(inf), 4},
+		{"Norm(0.5)", v.Norm(0.5), math.NaN()},
+		{"Norm2 overflow", big.Norm2(), math.Sqrt2 * 1e200},
+		{"Norm(3) overflow", big.Norm(3), math.Cbrt(2) * 1e200},
+		{"Norm2 Inf", VecFromSlice([]float64{inf, -inf, 1}).Norm2(), inf},
+		{"Norm2 NaN", VecFromSlice([]float64{inf, inf, math.NaN()}).Norm2(), math.NaN()},
+		{"Norm(3) Inf", VecFromSlice([]float64{inf, inf}).Norm(3), inf},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !floatClose(tt.got, tt.want, 1e-14) {
+				t.Fatal(tt.got, tt.want)
+			}
+		})
+	}
+}
+
+// floatClose reports whether a and b agree up to the relative tolerance tol,
+// NaNs and infinities have to match exactly
+func floatClose(a, b, tol float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return a == b
+	}
+	return math.Abs(a-b) <= tol*math.Max(1, math.Abs(b))
+}
+
+func TestMatrixNorms(t *testing.T) {
+	inf := math.Inf(1)
+	m, _ := MatrixFromSlice([][]float64{{1, -2}, {3, 4}})
+	s, _ := NewSVD(m, false)
+	mi, _ := MatrixFromSlice([][]float64{{inf, 1}, {2, -inf}})
+	mn, _ := MatrixFromSlice([][]float64{{inf, 1}, {2, math.NaN()}})
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"Norm1", m.Norm1(), 6},
+		{"NormInf", m.NormInf(), 7},
+		{"NormFrob", m.NormFrob(), math.Sqrt(30)},
+		{"Norm2", m.Norm2(), s.Values().Get(0)},
+		{"Norm2 of T", m.T().Norm2(), m.Norm2()},
+		{"NormFrob of T", m.T().NormFrob(), m.NormFrob()},
+		{"Norm1 of T", m.T().Norm1(), m.NormInf()},
+		{"NormFrob Inf", mi.NormFrob(), inf},
+		{"NormFrob NaN", mn.NormFrob(), math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !floatClose(tt.got, tt.want, 1e-12) {
+				t.Fatal(tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistances(t *testing.T) {
+	a := VecFromSlice([]float64{1, 2, 3})
+	b := VecFromSlice([]float64{4, 0, 3})
+	id, _ := IdMat(3, 3)
+	maha, e := a.MahalanobisDist(b, id.Scale(4))
+	if e != nil {
+		t.Fatal(e)
+	}
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"Euclidean", a.EuclideanDist(b), math.Sqrt(13)},
+		{"Manhattan", a.ManhattanDist(b), 5},
+		{"Chebyshev", a.ChebyshevDist(b), 3},
+		{"Cosine", a.CosineSimilarity(b), 13 / (math.Sqrt(14) * 5)},
+		{"Mahalanobis", maha, math.Sqrt(13) / 2},
+		{"Cosine zero", a.CosineDist(ZeroVec(3)), math.NaN()},
+		{"size mismatch", a.EuclideanDist(ZeroVec(2)), math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !floatClose(tt.got, tt.want, 1e-14) {
+				t.Fatal(tt.got, tt.want)
+			}
+		})
+	}
+	rng := rand.New(rand.NewSource(21))
+	x := randMat(3, 3, rng)
+	cov := x.TMul(x)
+	diff := a.Sub(b)
+	inv, _ := cov.Inverse()
+	if d, _ := a.MahalanobisDist(b, cov); math.Abs(d-math.Sqrt(diff.Dot(inv.MulVec(diff)))) > 1e-10 {
+		t.Fatal("Mahalanobis", d)
+	}
+	if _, e := a.MinkowskiDistSafe(b, 0); e == nil {
+		t.Fatal("expected error for p < 1")
+	}
+}
@@ -0,0 +1,387 @@
+/*	This file implements reading and writing of NumPy .npy files and .npz
+	archives (https://numpy.org/doc/stable/reference/generated/numpy.lib.format.html)
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// magic string at the start of every .npy file
+const npyMagic = "\x93NUMPY"
+
+// maxNpyBytes limits the size of the data of a .npy array (16 GiB)
+const maxNpyBytes = 1 << 34
+
+// npyChunk is the number of elements read at once
+const npyChunk = 1 << 16
+
+// ReadNpy reads a one or two dimensional array in .npy format from r.
+// Supported element types are float64, float32 and signed or unsigned
+// integers of both byte orders, in C or Fortran order.
+// One dimensional arrays are returned as n x 1 matrices.
+// Returns an error wrapping ErrFormat, if the input is malformed or
+// the data exceeds 16 GiB.
+func ReadNpy(r io.Reader) (m *Matrix, e error) {
+	shape, data, e := readNpyArray(r)
+	if e != nil {
+		return
+	}
+	m = npyMatrix(shape, data)
+	return
+}
+
+// ReadNpyVec reads a one dimensional array in .npy format from r.
+// Two dimensional arrays with a single row or column are accepted too.
+func ReadNpyVec(r io.Reader) (v *Vector, e error) {
+	shape, data, e := readNpyArray(r)
+	if e != nil {
+		return
+	}
+	if len(shape) == 2 && shape[0] != 1 && shape[1] != 1 {
+		e = fmt.Errorf("%w: npy: shape %v is not a vector", ErrFormat, shape)
+		return
+	}
+	v = &Vector{entries: data, inc: 1}
+	return
+}
+
+// WriteNpy writes the matrix a as two dimensional float64 array
+// in .npy format (C order) to w.
+func WriteNpy(w io.Writer, a Mat) (e error) {
+	r, c := a.Dims()
+	data := make([]float64, 0, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			data = append(data, a.At(i, j))
+		}
+	}
+	e = writeNpyArray(w, []int{r, c}, data)
+	return
+}
+
+// WriteNpyVec writes the vector v as one dimensional float64 array
+// in .npy format to w.
+func WriteNpyVec(w io.Writer, v *Vector) (e error) {
+	e = writeNpyArray(w, []int{v.Size()}, v.Slice())
+	return
+}
+
+// ReadNpz reads the arrays of a .npz archive of the given size from r.
+// Two dimensional arrays are returned in mats, one dimensional arrays
+// in vecs, both keyed by their name without the .npy extension.
+// Compressed archives (numpy.savez_compressed) are supported.
+func ReadNpz(r io.ReaderAt, size int64) (mats map[string]*Matrix, vecs map[string]*Vector, e error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		e = fmt.Errorf("%w: npz: %v", ErrFormat, err)
+		return
+	}
+	ms := make(map[string]*Matrix)
+	vs := make(map[string]*Vector)
+	for _, f := range zr.File {
+		name, ok := strings.CutSuffix(f.Name, ".npy")
+		if !ok {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			e = fmt.Errorf("%w: npz: %v", ErrFormat, err)
+			return
+		}
+		shape, data, err := readNpyArray(rc)
+		rc.Close()
+		if err != nil {
+			e = fmt.Errorf("npz: array %q: %w", name, err)
+			return
+		}
+		if len(shape) == 1 {
+			vs[name] = &Vector{entries: data, inc: 1}
+		} else {
+			ms[name] = npyMatrix(shape, data)
+		}
+	}
+	mats = ms
+	vecs = vs
+	return
+}
+
+// WriteNpz writes the matrices and vectors as named arrays of an
+// uncompressed .npz archive to w, like numpy.savez.
+// Returns an error wrapping ErrInvalidArgument, if a name is used twice.
+func WriteNpz(w io.Writer, mats map[string]*Matrix, vecs map[string]*Vector) (e error) {
+	// check names
+	for name := range vecs {
+		if _, ok := mats[name]; ok {
+			e = fmt.Errorf("%w: npz: duplicate array name %q", ErrInvalidArgument, name)
+			return
+		}
+	}
+	zw := zip.NewWriter(w)
+	// sort names for reproducible archives
+	names := make([]string, 0, len(mats)+len(vecs))
+	for name := range mats {
+		names = append(names, name)
+	}
+	for name := range vecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: name + ".npy", Method: zip.Store})
+		if err != nil {
+			e = err
+			return
+		}
+		if m, ok := mats[name]; ok {
+			e = WriteNpy(fw, m)
+		} else {
+			e = WriteNpyVec(fw, vecs[name])
+		}
+		if e != nil {
+			return
+		}
+	}
+	e = zw.Close()
+	return
+}
+
+// readNpyArray reads an array in .npy format and returns its shape and
+// its elements in C order
+func readNpyArray(r io.Reader) (shape []int, data []float64, e error) {
+	// read magic string, version and header length
+	pre := make([]byte, len(npyMagic)+2)
+	if _, err := io.ReadFull(r, pre); err != nil || string(pre[:len(npyMagic)]) != npyMagic {
+		e = fmt.Errorf("%w: npy: missing magic string", ErrFormat)
+		return
+	}
+	var headerLen int
+	switch major := pre[len(npyMagic)]; major {
+	case 1:
+		var n uint16
+		e = binary.Read(r, binary.LittleEndian, &n)
+		headerLen = int(n)
+	case 2, 3:
+		var n uint32
+		e = binary.Read(r, binary.LittleEndian, &n)
+		headerLen = int(n)
+	default:
+		e = fmt.Errorf("%w: npy: unsupported version %d", ErrFormat, major)
+		return
+	}
+	if e != nil {
+		e = fmt.Errorf("%w: npy: %v", ErrFormat, e)
+		return
+	}
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		e = fmt.Errorf("%w: npy: truncated header", ErrFormat)
+		return
+	}
+	descr, fortran, shape, e := parseNpyHeader(string(header))
+	if e != nil {
+		return
+	}
+	// decode elements
+	order, size, decode, e := npyDecoder(descr)
+	if e != nil {
+		return
+	}
+	// the shape is untrusted, check the size before reading
+	n := 1
+	for _, d := range shape {
+		if int64(n) > maxNpyBytes/int64(size)/int64(d) {
+			e = fmt.Errorf("%w: npy: shape %v exceeds %d bytes", ErrFormat, shape, maxNpyBytes)
+			return
+		}
+		n *= d
+	}
+	// read in chunks, so memory only grows with the data actually present
+	raw := make([]byte, min(n, npyChunk)*size)
+	data = make([]float64, 0, min(n, npyChunk))
+	for len(data) < n {
+		k := min(n-len(data), npyChunk)
+		if _, err := io.ReadFull(r, raw[:k*size]); err != nil {
+			data = nil
+			e = fmt.Errorf("%w: npy: truncated data", ErrFormat)
+			return
+		}
+		for l := 0; l < k; l++ {
+			data = append(data, decode(order, raw[l*size:]))
+		}
+	}
+	// reorder column-major data
+	if fortran && len(shape) == 2 {
+		rows, cols := shape[0], shape[1]
+		c := make([]float64, n)
+		for j := 0; j < cols; j++ {
+			for i := 0; i < rows; i++ {
+				c[i*cols+j] = data[j*rows+i]
+			}
+		}
+		data = c
+	}
+	return
+}
+
+// parseNpyHeader parses the header dictionary of a .npy file, e.g.
+// {'descr': '<f8', 'fortran_order': False, 'shape': (3, 4), }
+func parseNpyHeader(h string) (descr string, fortran bool, shape []int, e error) {
+	e = fmt.Errorf("%w: npy: invalid header %q", ErrFormat, h)
+	// descr
+	_, rest, ok := strings.Cut(h, "'descr':")
+	if !ok {
+		return
+	}
+	rest = strings.TrimSpace(rest)
+	if len(rest) < 2 || rest[0] != '\'' {
+		return
+	}
+	descr, _, ok = strings.Cut(rest[1:], "'")
+	if !ok {
+		return
+	}
+	// fortran order
+	_, rest, ok = strings.Cut(h, "'fortran_order':")
+	if !ok {
+		return
+	}
+	rest = strings.TrimSpace(rest)
+	fortran = strings.HasPrefix(rest, "True")
+	if !fortran && !strings.HasPrefix(rest, "False") {
+		return
+	}
+	// shape
+	_, rest, ok = strings.Cut(h, "'shape':")
+	if !ok {
+		return
+	}
+	rest = strings.TrimSpace(rest)
+	if len(rest) < 2 || rest[0] != '(' {
+		return
+	}
+	tuple, _, ok := strings.Cut(rest[1:], ")")
+	if !ok {
+		return
+	}
+	for _, f := range strings.Split(tuple, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		d, err := strconv.Atoi(strings.TrimSuffix(f, "L"))
+		if err != nil || d <= 0 {
+			return
+		}
+		shape = append(shape, d)
+	}
+	if len(shape) != 1 && len(shape) != 2 {
+		e = fmt.Errorf("%w: npy: unsupported shape %v, need 1 or 2 dimensions", ErrFormat, shape)
+		return
+	}
+	e = nil
+	return
+}
+
+// npyDecoder returns the byte order, the element size and a function
+// converting one element of the given type to float64
+func npyDecoder(descr string) (order binary.ByteOrder, size int, decode func(binary.ByteOrder, []byte) float64, e error) {
+	if len(descr) < 3 {
+		e = fmt.Errorf("%w: npy: unsupported type %q", ErrFormat, descr)
+		return
+	}
+	switch descr[0] {
+	case '<', '|', '=':
+		order = binary.LittleEndian
+	case '>':
+		order = binary.BigEndian
+	default:
+		e = fmt.Errorf("%w: npy: unsupported type %q", ErrFormat, descr)
+		return
+	}
+	switch descr[1:] {
+	case "f8":
+		size = 8
+		decode = func(o binary.ByteOrder, b []byte) float64 { return math.Float64frombits(o.Uint64(b)) }
+	case "f4":
+		size = 4
+		decode = func(o binary.ByteOrder, b []byte) float64 { return float64(math.Float32frombits(o.Uint32(b))) }
+	case "i8":
+		size = 8
+		decode = func(o binary.ByteOrder, b []byte) float64 { return float64(int64(o.Uint64(b))) }
+	case "i4":
+		size = 4
+		decode = func(o binary.ByteOrder, b []byte) float64 { return float64(int32(o.Uint32(b))) }
+	case "i2":
+		size = 2
+		decode = func(o binary.ByteOrder, b []byte) float64 { return float64(int16(o.Uint16(b))) }
+	case "i1":
+		size = 1
+		decode = func(o binary.ByteOrder, b []byte) float64 { return float64(int8(b[0])) }
+	case "u8":
+		size = 8
+		decode = func(o binary.ByteOrder, b []byte) float64 { return float64(o.Uint64(b)) }
+	case "u4":
+		size = 4
+		decode = func(o binary.ByteOrder, b []byte) float64 { return float64(o.Uint32(b)) }
+	case "u2":
+		size = 2
+		decode = func(o binary.ByteOrder, b []byte) float64 { return float64(o.Uint16(b)) }
+	case "u1":
+		size = 1
+		decode = func(o binary.ByteOrder, b []byte) float64 { return float64(b[0]) }
+	default:
+		e = fmt.Errorf("%w: npy: unsupported type %q", ErrFormat, descr)
+	}
+	return
+}
+
+// writeNpyArray writes the elements in C order as float64 array
+// with the given shape in .npy format version 1.0
+func writeNpyArray(w io.Writer, shape []int, data []float64) (e error) {
+	dims := make([]string, len(shape))
+	for k, d := range shape {
+		dims[k] = strconv.Itoa(d)
+	}
+	tuple := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		tuple += ","
+	}
+	header := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': (%s), }", tuple)
+	// pad with spaces and a newline, such that the data is 64 byte aligned
+	total := len(npyMagic) + 4 + len(header) + 1
+	header += strings.Repeat(" ", (64-total%64)%64) + "\n"
+	var buf bytes.Buffer
+	buf.WriteString(npyMagic)
+	buf.Write([]byte{1, 0})
+	binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	buf.WriteString(header)
+	raw := make([]byte, 8*len(data))
+	for k, x := range data {
+		binary.LittleEndian.PutUint64(raw[8*k:], math.Float64bits(x))
+	}
+	buf.Write(raw)
+	_, e = buf.WriteTo(w)
+	return
+}
+
+// npyMatrix returns the elements in C order as matrix,
+// one dimensional arrays as column
+func npyMatrix(shape []int, data []float64) (m *Matrix) {
+	rows, cols := shape[0], 1
+	if len(shape) == 2 {
+		cols = shape[1]
+	}
+	m = matFromEntries(rows, cols, data)
+	return
+}
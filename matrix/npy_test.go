@@ -0,0 +1,137 @@
+package matrix
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// rawNpy returns a version 1.0 npy file with the given header and data
+func rawNpy(header string, data []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString("\x93NUMPY\x01\x00")
+	binary.Write(&b, binary.LittleEndian, uint16(len(header)))
+	b.WriteString(header)
+	b.Write(data)
+	return b.Bytes()
+}
+
+// f8Header returns the npy header of a float64 array with the given shape
+func f8Header(shape string) []byte {
+	return rawNpy("{'descr': '<f8', 'fortran_order': False, 'shape': "+shape+", }\n", nil)
+}
+
+func TestNpyRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(18))
+	for _, a := range []*Matrix{randMat(3, 5, rng), randMat(3, 5, rng).T(), randMat(300, 301, rng)} {
+		var buf bytes.Buffer
+		if e := WriteNpy(&buf, a); e != nil {
+			t.Fatal(e)
+		}
+		// the data starts at a multiple of 64 bytes
+		if (buf.Len()-8*a.Rows()*a.Cols())%64 != 0 {
+			t.Fatal("header not aligned", buf.Len())
+		}
+		b, e := ReadNpy(&buf)
+		if e != nil {
+			t.Fatal(e)
+		}
+		matClose(t, "npy", b, a, 0)
+	}
+	v := randVec(7, rng)
+	var buf bytes.Buffer
+	WriteNpyVec(&buf, v)
+	w, e := ReadNpyVec(&buf)
+	if e != nil {
+		t.Fatal(e)
+	}
+	vecClose(t, "vector", w, v, 0)
+}
+
+func TestReadNpyTypes(t *testing.T) {
+	// [[1,2,3],[4,5,-6]] in different element types and orders
+	var f4, i2 bytes.Buffer
+	for _, x := range []float32{1, 4, 2, 5, 3, -6} {
+		binary.Write(&f4, binary.LittleEndian, math.Float32bits(x))
+	}
+	for _, x := range []int16{1, 2, 3, 4, 5, -6} {
+		binary.Write(&i2, binary.BigEndian, x)
+	}
+	want, _ := MatrixFromSlice([][]float64{{1, 2, 3}, {4, 5, -6}})
+	col, _ := MatrixFromSlice([][]float64{{1}, {2}, {255}})
+	tests := []struct {
+		name string
+		in   []byte
+		want *Matrix
+	}{
+		{"float32 fortran", rawNpy("{'descr': '<f4', 'fortran_order': True, 'shape': (2, 3), }\n", f4.Bytes()), want},
+		{"int16 big endian", rawNpy("{'descr': '>i2', 'fortran_order': False, 'shape': (2, 3), }\n", i2.Bytes()), want},
+		{"uint8 vector", rawNpy("{'descr': '|u1', 'fortran_order': False, 'shape': (3,), }\n", []byte{1, 2, 255}), col},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, e := ReadNpy(bytes.NewReader(tt.in))
+			if e != nil {
+				t.Fatal(e)
+			}
+			matClose(t, tt.name, m, tt.want, 0)
+		})
+	}
+}
+
+func TestReadNpyErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+	}{
+		{"magic", []byte("junk")},
+		{"complex", rawNpy("{'descr': '<c16', 'fortran_order': False, 'shape': (1,), }\n", make([]byte, 16))},
+		{"3 dimensions", rawNpy("{'descr': '<f8', 'fortran_order': False, 'shape': (1, 1, 1), }\n", make([]byte, 8))},
+		{"truncated", rawNpy("{'descr': '<f8', 'fortran_order': False, 'shape': (2,), }\n", make([]byte, 8))},
+		{"truncated large", append(f8Header("(1000, 1000)"), make([]byte, 80)...)},
+		{"huge", f8Header("(1000000000000, 1000000000000)")},
+		{"overflow", f8Header("(9223372036854775807,)")},
+		{"too large", f8Header("(3000000000,)")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, e := ReadNpy(bytes.NewReader(tt.in)); !errors.Is(e, ErrFormat) {
+				t.Fatal(e)
+			}
+		})
+	}
+}
+
+func TestNpz(t *testing.T) {
+	rng := rand.New(rand.NewSource(18))
+	a := randMat(3, 5, rng)
+	v := randVec(7, rng)
+	var buf bytes.Buffer
+	if e := WriteNpz(&buf, map[string]*Matrix{"a": a}, map[string]*Vector{"v": v}); e != nil {
+		t.Fatal(e)
+	}
+	mats, vecs, e := ReadNpz(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if e != nil || len(mats) != 1 || len(vecs) != 1 {
+		t.Fatal(e)
+	}
+	matClose(t, "npz matrix", mats["a"], a, 0)
+	vecClose(t, "npz vector", vecs["v"], v, 0)
+	// compressed archive as written by numpy.savez_compressed
+	buf.Reset()
+	zw := zip.NewWriter(&buf)
+	fw, _ := zw.CreateHeader(&zip.FileHeader{Name: "x.npy", Method: zip.Deflate})
+	WriteNpy(fw, a)
+	zw.Close()
+	mats, _, e = ReadNpz(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if e != nil {
+		t.Fatal(e)
+	}
+	matClose(t, "npz deflate", mats["x"], a, 0)
+	if e := WriteNpz(&buf, map[string]*Matrix{"a": a}, map[string]*Vector{"a": v}); !errors.Is(e, ErrInvalidArgument) {
+		t.Fatal(e)
+	}
+}
@@ -0,0 +1,243 @@
+/*	This file implements the concurrency settings and the parallel,
+	cache-blocked kernels of the matrix package.
+	Large operations are split across goroutines. The number of goroutines
+	is limited by the package-level setting (SetWorkers) or per call.
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// minParallelWork is the minimal number of operations (entries or
+// multiply-adds), which is worth to be processed by one goroutine.
+const minParallelWork = 1 << 14
+
+// tile sizes of the blocked multiplication, such that a tile of b
+// (blockK x blockN entries) fits into the L2 cache
+const (
+	blockK = 64
+	blockN = 256
+)
+
+// workers holds the package-level number of goroutines
+var workers atomic.Int64
+
+func init() {
+	workers.Store(int64(runtime.GOMAXPROCS(0)))
+}
+
+// SetWorkers sets the maximal number of goroutines, which are used
+// by the operations of this package. n < 1 resets it to GOMAXPROCS.
+// Returns the previous setting.
+func SetWorkers(n int) (prev int) {
+	if n < 1 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	prev = int(workers.Swap(int64(n)))
+	return
+}
+
+// Workers returns the maximal number of goroutines, which are used
+// by the operations of this package.
+func Workers() int {
+	return int(workers.Load())
+}
+
+// MulParallel computes the matrix product c = a * b with up to workers
+// goroutines. workers < 1 uses the package-level setting.
+// Dimension mismatch (a.Cols() != b.Rows()) returns nil.
+func (a *Matrix) MulParallel(b *Matrix, workers int) (c *Matrix) {
+	c, _ = a.MulParallelSafe(b, workers)
+	return
+}
+
+// MulParallelSafe computes the matrix product c = a * b with up to workers
+// goroutines, like MulParallel.
+// Returns a DimensionError, if a.Cols() != b.Rows().
+func (a *Matrix) MulParallelSafe(b *Matrix, workers int) (c *Matrix, e error) {
+	// check if dimensions match
+	if a.cols != b.rows {
+		e = &DimensionError{Op: "MulParallel", A: a.shape(), B: b.shape()}
+		return
+	}
+	c, _ = ZeroMat(a.rows, b.cols)
+	c.mulAddN(a, b, workersOrDefault(workers))
+	return
+}
+
+// AddParallel computes c = a + b with up to workers goroutines.
+// workers < 1 uses the package-level setting.
+// Dimension mismatch returns nil.
+func (a *Matrix) AddParallel(b *Matrix, workers int) (c *Matrix) {
+	c, _ = a.AddParallelSafe(b, workers)
+	return
+}
+
+// AddParallelSafe computes c = a + b with up to workers goroutines,
+// like AddParallel.
+// Returns a DimensionError, if the dimensions don't match.
+func (a *Matrix) AddParallelSafe(b *Matrix, workers int) (c *Matrix, e error) {
+	// check if dimensions match
+	if a.rows != b.rows || a.cols != b.cols {
+		e = &DimensionError{Op: "AddParallel", A: a.shape(), B: b.shape()}
+		return
+	}
+	c, _ = ZeroMat(a.rows, a.cols)
+	c.zipOf(a, b, kernel{op: opAdd}, workersOrDefault(workers))
+	return
+}
+
+// CWiseProdParallel computes the component-wise product of a and b
+// with up to workers goroutines. workers < 1 uses the package-level setting.
+// Dimension mismatch returns nil.
+func (a *Matrix) CWiseProdParallel(b *Matrix, workers int) (c *Matrix) {
+	c, _ = a.CWiseProdParallelSafe(b, workers)
+	return
+}
+
+// CWiseProdParallelSafe computes the component-wise product of a and b
+// with up to workers goroutines, like CWiseProdParallel.
+// Returns a DimensionError, if the dimensions don't match.
+func (a *Matrix) CWiseProdParallelSafe(b *Matrix, workers int) (c *Matrix, e error) {
+	// check if dimensions match
+	if a.rows != b.rows || a.cols != b.cols {
+		e = &DimensionError{Op: "CWiseProdParallel", A: a.shape(), B: b.shape()}
+		return
+	}
+	c, _ = ZeroMat(a.rows, a.cols)
+	c.zipOf(a, b, kernel{op: opCWiseProd}, workersOrDefault(workers))
+	return
+}
+
+// ApplyFuncParallel returns a new matrix which contains the entries of a
+// after applying func f. The entries are processed by up to workers
+// goroutines, so f has to be safe for concurrent use.
+// workers < 1 uses the package-level setting.
+func (a *Matrix) ApplyFuncParallel(f func(float64) float64, workers int) (m *Matrix) {
+	m, _ = ZeroMat(a.rows, a.cols)
+	m.zipOf(a, nil, kernel{op: opApply, f: f}, workersOrDefault(workers))
+	return
+}
+
+// workersOrDefault returns n or the package-level setting, if n < 1.
+func workersOrDefault(n int) int {
+	if n < 1 {
+		return Workers()
+	}
+	return n
+}
+
+// numChunks returns in how many chunks n operations should be split,
+// such that every chunk has at least grain operations.
+func numChunks(n, workers, grain int) int {
+	chunks := n / max(grain, 1)
+	return max(min(chunks, workers), 1)
+}
+
+// parallelFor splits [0, n) into chunks ranges [lo, hi) of similar size
+// and calls body on each of them in its own goroutine.
+func parallelFor(n, chunks int, body func(lo, hi int)) {
+	var wg sync.WaitGroup
+	wg.Add(chunks)
+	for t := 0; t < chunks; t++ {
+		lo := t * n / chunks
+		hi := (t + 1) * n / chunks
+		go func() {
+			defer wg.Done()
+			body(lo, hi)
+		}()
+	}
+	wg.Wait()
+}
+
+// mulAddN computes c = c + a * b with up to workers goroutines,
+// which process disjoint blocks of rows of c.
+// c needs cstride = 1 and must not share storage with a or b.
+func (c *Matrix) mulAddN(a, b *Matrix, workers int) {
+	// the inner loop needs the rows of b as slices
+	if b.cstride != 1 {
+		b = b.CopyMat()
+	}
+	work := a.rows * a.cols * b.cols
+	chunks := numChunks(work, workers, minParallelWork)
+	chunks = min(chunks, a.rows)
+	if chunks > 1 {
+		parallelFor(a.rows, chunks, func(lo, hi int) {
+			c.mulAddRows(a, b, lo, hi)
+		})
+		return
+	}
+	c.mulAddRows(a, b, 0, a.rows)
+}
+
+// mulAddRows computes the rows lo, ..., hi-1 of c = c + a * b.
+// The loops over k and j are tiled, such that a tile of b stays in the
+// cache while it is used for all rows.
+func (c *Matrix) mulAddRows(a, b *Matrix, lo, hi int) {
+	for kk := 0; kk < a.cols; kk += blockK {
+		kEnd := min(kk+blockK, a.cols)
+		for jj := 0; jj < b.cols; jj += blockN {
+			jEnd := min(jj+blockN, b.cols)
+			for i := lo; i < hi; i++ {
+				cRow := c.row(i)[jj:jEnd]
+				for k := kk; k < kEnd; k++ {
+					aik := a.getEntry(i, k)
+					bRow := b.row(k)[jj:jEnd]
+					for j := range cRow {
+						cRow[j] += aik * bRow[j]
+					}
+				}
+			}
+		}
+	}
+}
+
+// mulTRowsParallel computes c = a * b^T, where the rows of c are split
+// into chunks, which are processed concurrently.
+// a and b need cstride = 1.
+func (c *Matrix) mulTRowsParallel(a, b *Matrix, chunks int) {
+	parallelFor(a.rows, chunks, func(lo, hi int) {
+		c.mulTRows(a, b, lo, hi)
+	})
+}
+
+// mulTRows computes the rows lo, ..., hi-1 of c = a * b^T.
+// a and b need cstride = 1.
+func (c *Matrix) mulTRows(a, b *Matrix, lo, hi int) {
+	for i := lo; i < hi; i++ {
+		aRow := a.row(i)
+		cRow := c.row(i)
+		for j := 0; j < b.rows; j++ {
+			bRow := b.row(j)[:len(aRow)]
+			var sum float64 = 0
+			for k := range aRow {
+				sum += aRow[k] * bRow[k]
+			}
+			cRow[j] = sum
+		}
+	}
+}
+
+// mulVecRowsParallel computes w = a * v, where the rows of a are split
+// into chunks, which are processed concurrently.
+func (w *Vector) mulVecRowsParallel(a *Matrix, v *Vector, chunks int) {
+	parallelFor(a.rows, chunks, func(lo, hi int) {
+		w.mulVecRows(a, v, lo, hi)
+	})
+}
+
+// mulVecRows computes the elements lo, ..., hi-1 of w = a * v.
+func (w *Vector) mulVecRows(a *Matrix, v *Vector, lo, hi int) {
+	for i := lo; i < hi; i++ {
+		var sum float64 = 0
+		for j := 0; j < a.cols; j++ {
+			sum += a.getEntry(i, j) * v.at(j)
+		}
+		w.setAt(i, sum)
+	}
+}
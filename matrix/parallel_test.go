@@ -0,0 +1,94 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestParallelKernels(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	prev := SetWorkers(4)
+	defer SetWorkers(prev)
+	a := randMat(130, 300, rng)
+	b := randMat(300, 270, rng)
+	ref := naiveMul(a, b)
+	c := randMat(300, 400, rng)
+	d := randMat(300, 400, rng)
+	sum := c.Add(d)
+	tests := []struct {
+		name      string
+		got, want *Matrix
+		tol       float64
+	}{
+		{"Mul", a.Mul(b), ref, 1e-9},
+		{"MulParallel", a.MulParallel(b, 3), ref, 1e-9},
+		{"TMul", a.T().TMul(b), ref, 1e-9},
+		{"MulT", a.MulT(b.T()), ref, 1e-9},
+		{"AddParallel", c.AddParallel(d, 7), sum, 0},
+		{"Add transposed", c.T().Add(d.T()), naiveT(sum), 0},
+		{"CWiseProdParallel", c.CWiseProdParallel(d, 0), c.CWiseProd(d), 0},
+		{"ApplyFuncParallel", c.ApplyFuncParallel(func(x float64) float64 { return 2 * x }, 5), c.Scale(2), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matClose(t, tt.name, tt.got, tt.want, tt.tol)
+		})
+	}
+	v := randVec(400, rng)
+	vecClose(t, "MulVec", c.MulVec(v), naiveMul(c, v.Mat()).GetCol(0), 1e-9)
+	big := randVec(100000, rng)
+	big2 := big.CopyVec()
+	big2.AXPY(2, big)
+	vecClose(t, "AXPY", big2, big.Scale(3), 1e-12)
+}
+
+func TestSetWorkers(t *testing.T) {
+	prev := SetWorkers(3)
+	if Workers() != 3 || SetWorkers(prev) != 3 {
+		t.Fatal("SetWorkers")
+	}
+}
+
+func TestParallelNaN(t *testing.T) {
+	a, _ := MatrixFromSlice([][]float64{{0}})
+	b, _ := MatrixFromSlice([][]float64{{math.Inf(1)}})
+	// 0 * Inf is NaN
+	tests := []struct {
+		name string
+		c    *Matrix
+	}{
+		{"Mul", a.Mul(b)},
+		{"MulParallel", a.MulParallel(b, 2)},
+		{"TMul", a.TMul(b)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !math.IsNaN(tt.c.Get(0, 0)) {
+				t.Fatal(tt.c.Get(0, 0))
+			}
+		})
+	}
+}
+
+func TestParallelSafe(t *testing.T) {
+	a, _ := ZeroMat(2, 3)
+	b, _ := ZeroMat(2, 3)
+	var de *DimensionError
+	if _, e := a.MulParallelSafe(b, 2); !errors.As(e, &de) || de.Op != "MulParallel" {
+		t.Fatal(e)
+	}
+	if c, e := a.MulParallelSafe(b.T(), 0); e != nil || c.Rows() != 2 || c.Cols() != 2 {
+		t.Fatal(e)
+	}
+	if _, e := a.AddParallelSafe(b.T(), 2); !errors.As(e, &de) || de.Op != "AddParallel" {
+		t.Fatal(e)
+	}
+	if c, e := a.AddParallelSafe(b, 2); e != nil || c.Rows() != 2 {
+		t.Fatal(e)
+	}
+	if _, e := a.CWiseProdParallelSafe(b.T(), 2); !errors.As(e, &de) || de.Op != "CWiseProdParallel" {
+		t.Fatal(e)
+	}
+}
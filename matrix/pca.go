@@ -0,0 +1,134 @@
+/*	This file implements the principal component analysis of a data matrix,
+	whose rows are the observations
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// NewPCA computes the principal component analysis of the data matrix a
+// with the observations as rows and keeps the k leading components.
+// The components are computed from the SVD of the centered data, so the
+// covariance matrix is never formed. The sign of every component is chosen
+// such that its entry with the largest absolute value is positive.
+// Returns an error, if a has less than two rows, k is not in
+// [1, min(rows, cols)] or the SVD doesn't converge.
+func NewPCA(a Mat, k int) (p *PCA, e error) {
+	p, e = newPCA(asMatrix(a), k)
+	return
+}
+
+// newPCA implements NewPCA for a dense matrix
+func newPCA(a *Matrix, k int) (p *PCA, e error) {
+	// check arguments
+	if a.rows < 2 {
+		e = fmt.Errorf("%w: principal components of %d observations", ErrInvalidArgument, a.rows)
+		return
+	}
+	if k < 1 || k > min(a.rows, a.cols) {
+		e = fmt.Errorf("%w: %d components not in [1, %d]", ErrInvalidArgument, k, min(a.rows, a.cols))
+		return
+	}
+	centered, means := a.Center()
+	f, e := newSVD(centered, false)
+	if e != nil {
+		return
+	}
+	p = new(PCA)
+	p.means = means
+	// variance of the i-th component is s_i^2 / (rows-1)
+	p.variances = f.values.ApplyFunc(func(s float64) float64 {
+		return s * s / float64(a.rows-1)
+	})
+	p.components, _ = ZeroMat(a.cols, k)
+	for j := 0; j < k; j++ {
+		// deterministic sign
+		var sign, largest float64 = 1, 0
+		for i := 0; i < a.cols; i++ {
+			if v := f.v.getEntry(i, j); math.Abs(v) > largest {
+				largest = math.Abs(v)
+				sign = math.Copysign(1, v)
+			}
+		}
+		for i := 0; i < a.cols; i++ {
+			p.components.entries[i*k+j] = sign * f.v.getEntry(i, j)
+		}
+	}
+	return
+}
+
+// Components returns the principal axes as orthonormal columns of a
+// cols x k matrix, ordered by decreasing explained variance.
+func (p *PCA) Components() (m *Matrix) {
+	m = p.components.CopyMat()
+	return
+}
+
+// Means returns the column means of the data, which are subtracted
+// before projecting.
+func (p *PCA) Means() (v *Vector) {
+	v = p.means.CopyVec()
+	return
+}
+
+// ExplainedVariance returns the variances of the data along the k components.
+func (p *PCA) ExplainedVariance() (v *Vector) {
+	v = p.variances.GetSubVec(0, p.components.cols-1)
+	return
+}
+
+// ExplainedVarianceRatio returns the fractions of the total variance of the
+// data explained by the k components.
+// The ratios are NaN for constant data.
+func (p *PCA) ExplainedVarianceRatio() (v *Vector) {
+	total := p.variances.Sum()
+	v = p.ExplainedVariance()
+	v.ApplyFuncInPlace(func(x float64) float64 { return x / total })
+	return
+}
+
+// Project returns the coordinates (x - means) * Components() of the
+// observations x in the space of the principal components.
+// Returns nil, if x doesn't have the columns of the data.
+func (p *PCA) Project(x *Matrix) (y *Matrix) {
+	y, _ = p.ProjectSafe(x)
+	return
+}
+
+// ProjectSafe returns the coordinates of the observations x in the space
+// of the principal components, like Project.
+// Returns a DimensionError, if x doesn't have the columns of the data.
+func (p *PCA) ProjectSafe(x *Matrix) (y *Matrix, e error) {
+	// check sizes
+	if x.cols != p.components.rows {
+		e = &DimensionError{Op: "Project", A: x.shape(), B: p.components.shape()}
+		return
+	}
+	y = x.SubRowVec(p.means).Mul(p.components)
+	return
+}
+
+// Reconstruct returns the observations y * Components()^T + means
+// from their coordinates y in the space of the principal components.
+// Returns nil, if y doesn't have k columns.
+func (p *PCA) Reconstruct(y *Matrix) (x *Matrix) {
+	x, _ = p.ReconstructSafe(y)
+	return
+}
+
+// ReconstructSafe returns the observations from their coordinates y in
+// the space of the principal components, like Reconstruct.
+// Returns a DimensionError, if y doesn't have k columns.
+func (p *PCA) ReconstructSafe(y *Matrix) (x *Matrix, e error) {
+	// check sizes
+	if y.cols != p.components.cols {
+		e = &DimensionError{Op: "Reconstruct", A: y.shape(), B: p.components.shape()}
+		return
+	}
+	x = y.MulT(p.components).AddRowVec(p.means)
+	return
+}
@@ -0,0 +1,66 @@
+package matrix
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPCA(t *testing.T) {
+	rng := rand.New(rand.NewSource(24))
+	a := correlatedData(200, rng)
+	d := a.Cols()
+	p, e := NewPCA(a, d)
+	if e != nil {
+		t.Fatal(e)
+	}
+	// the variances are the eigenvalues of the covariance in descending order
+	eig, _ := NewEigenSym(a.Cov())
+	ev := eig.Values()
+	pv := p.ExplainedVariance()
+	for i := 0; i < d; i++ {
+		if math.Abs(pv.Get(i)-ev.Get(d-1-i)) > 1e-6*ev.Get(d-1) {
+			t.Fatal("ExplainedVariance", pv.Get(i), ev.Get(d-1-i))
+		}
+	}
+	if math.Abs(p.ExplainedVarianceRatio().Sum()-1) > 1e-12 {
+		t.Fatal("ExplainedVarianceRatio")
+	}
+	w := p.Components()
+	id, _ := IdMat(d, d)
+	matClose(t, "W^T W", w.TMul(w), id, 1e-10)
+	y := p.Project(a)
+	matClose(t, "Reconstruct", p.Reconstruct(y), a, 1e-6)
+	pc := y.Cov()
+	for i := 0; i < d; i++ {
+		if math.Abs(pc.Get(i, i)-pv.Get(i)) > 1e-6*pv.Get(0) {
+			t.Fatal("variance of the projection", i)
+		}
+	}
+}
+
+func TestPCAComponents(t *testing.T) {
+	rng := rand.New(rand.NewSource(24))
+	a := correlatedData(50, rng)
+	p, e := NewPCA(a, 2)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if p.Components().Cols() != 2 || p.Project(a).Cols() != 2 || p.ExplainedVarianceRatio().Size() != 2 {
+		t.Fatal("number of components")
+	}
+	wrong, _ := ZeroMat(3, 1)
+	if p.Project(wrong) != nil {
+		t.Fatal("Project of wrong size should be nil")
+	}
+	if _, e := NewPCA(a, 5); e == nil {
+		t.Fatal("expected error for k > cols")
+	}
+	// fewer samples than variables
+	wide := randMat(3, 6, rng)
+	pw, e := NewPCA(wide, 3)
+	if e != nil {
+		t.Fatal(e)
+	}
+	matClose(t, "wide", pw.Reconstruct(pw.Project(wide)), wide, 1e-10)
+}
@@ -0,0 +1,124 @@
+/*	This file implements the preconditioners for the iterative solvers
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"fmt"
+)
+
+// NewJacobi creates the Jacobi preconditioner M = diag(d).
+// The diagonal can be obtained with Diag() of Matrix, CSR or CSC.
+// Returns ErrSingular, if d has a zero entry.
+func NewJacobi(d *Vector) (p *Jacobi, e error) {
+	inv := ZeroVec(d.Size())
+	for i := range inv.entries {
+		di := d.at(i)
+		if di == 0 {
+			e = fmt.Errorf("%w: zero diagonal entry %d", ErrSingular, i)
+			return
+		}
+		inv.entries[i] = 1 / di
+	}
+	p = &Jacobi{inv: inv}
+	return
+}
+
+// Apply returns M^-1 * r. Dimension mismatch returns nil.
+func (p *Jacobi) Apply(r *Vector) (z *Vector) {
+	z = r.CWiseProd(p.inv)
+	return
+}
+
+// NewILU0 computes the incomplete LU factorization of the square sparse
+// matrix a, which keeps the sparsity pattern of a.
+// Returns ErrSingular, if a diagonal entry is missing or a pivot vanishes.
+func NewILU0(a *CSR) (p *ILU0, e error) {
+	// check if square
+	if a.major != a.minor {
+		e = ErrNotSquare
+		return
+	}
+	n := a.major
+	lu := &CSR{compressed{
+		major:   n,
+		minor:   n,
+		indptr:  append([]int(nil), a.indptr...),
+		indices: append([]int(nil), a.indices...),
+		values:  append([]float64(nil), a.values...),
+	}}
+	// find diagonal entries
+	diag := make([]int, n)
+	for i := 0; i < n; i++ {
+		d, ok := lu.find(i, i)
+		if !ok {
+			e = fmt.Errorf("%w: missing diagonal entry %d", ErrSingular, i)
+			return
+		}
+		diag[i] = d
+	}
+	// position of the columns of the current row, -1 if not in pattern
+	pos := make([]int, n)
+	for j := range pos {
+		pos[j] = -1
+	}
+	for i := 0; i < n; i++ {
+		for q := lu.indptr[i]; q < lu.indptr[i+1]; q++ {
+			pos[lu.indices[q]] = q
+		}
+		// eliminate with the rows k < i in the pattern
+		for q := lu.indptr[i]; q < diag[i]; q++ {
+			k := lu.indices[q]
+			pivot := lu.values[diag[k]]
+			if pivot == 0 {
+				e = fmt.Errorf("%w: zero pivot %d", ErrSingular, k)
+				return
+			}
+			lu.values[q] /= pivot
+			lik := lu.values[q]
+			for r := diag[k] + 1; r < lu.indptr[k+1]; r++ {
+				if s := pos[lu.indices[r]]; s >= 0 {
+					lu.values[s] -= lik * lu.values[r]
+				}
+			}
+		}
+		if lu.values[diag[i]] == 0 {
+			e = fmt.Errorf("%w: zero pivot %d", ErrSingular, i)
+			return
+		}
+		for q := lu.indptr[i]; q < lu.indptr[i+1]; q++ {
+			pos[lu.indices[q]] = -1
+		}
+	}
+	p = &ILU0{lu: lu, diag: diag}
+	return
+}
+
+// Apply returns (L*U)^-1 * r. Dimension mismatch returns nil.
+func (p *ILU0) Apply(r *Vector) (z *Vector) {
+	lu := p.lu
+	n := lu.major
+	// check size
+	if r.Size() != n {
+		return
+	}
+	z = r.CopyVec()
+	// forward substitution with unit lower triangle
+	for i := 0; i < n; i++ {
+		sum := z.entries[i]
+		for q := lu.indptr[i]; q < p.diag[i]; q++ {
+			sum -= lu.values[q] * z.entries[lu.indices[q]]
+		}
+		z.entries[i] = sum
+	}
+	// back substitution with upper triangle
+	for i := n - 1; i >= 0; i-- {
+		sum := z.entries[i]
+		for q := p.diag[i] + 1; q < lu.indptr[i+1]; q++ {
+			sum -= lu.values[q] * z.entries[lu.indices[q]]
+		}
+		z.entries[i] = sum / lu.values[p.diag[i]]
+	}
+	return
+}
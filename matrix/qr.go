@@ -0,0 +1,235 @@
+/*	This file implements the Householder QR decomposition and least squares
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"math"
+)
+
+// NewQR computes the QR decomposition a = Q * R of the matrix a
+// with Householder reflections. a needs at least as many rows as columns.
+// Q is a rows x cols matrix with orthonormal columns and R is a
+// cols x cols upper triangular matrix. The matrix a is not modified.
+func NewQR(a Mat) (f *QR, e error) {
+	f, e = factorQR(asMatrix(a), false)
+	return
+}
+
+// factorQR computes the QR decomposition of a.
+// If pivot is set, the columns are reordered in every step such that
+// the column with the largest remaining norm is eliminated first (a * P = Q * R).
+func factorQR(a *Matrix, pivot bool) (f *QR, e error) {
+	// check dimensions
+	if a.rows < a.cols {
+		// QR needs rows >= cols
+		e = &DimensionError{Op: "QR", A: a.shape()}
+		return
+	}
+	m := a.rows
+	n := a.cols
+	// create factorization
+	f = new(QR)
+	f.qr = a.CopyMat()
+	f.rdiag = make([]float64, n)
+	f.perm = make([]int, n)
+	for j := range f.perm {
+		f.perm[j] = j
+	}
+	qr := f.qr.entries
+	for k := 0; k < n; k++ {
+		// swap the column with the largest remaining norm to position k
+		if pivot {
+			p := k
+			var maxNorm float64 = -1
+			for j := k; j < n; j++ {
+				var nrm float64 = 0
+				for i := k; i < m; i++ {
+					nrm = math.Hypot(nrm, qr[i*n+j])
+				}
+				if nrm > maxNorm {
+					maxNorm = nrm
+					p = j
+				}
+			}
+			if p != k {
+				for i := 0; i < m; i++ {
+					qr[i*n+p], qr[i*n+k] = qr[i*n+k], qr[i*n+p]
+				}
+				f.perm[p], f.perm[k] = f.perm[k], f.perm[p]
+			}
+		}
+		// compute 2-norm of k-th column below the diagonal
+		var nrm float64 = 0
+		for i := k; i < m; i++ {
+			nrm = math.Hypot(nrm, qr[i*n+k])
+		}
+		if nrm == 0 {
+			f.rdiag[k] = 0
+			continue
+		}
+		// form k-th Householder vector
+		if qr[k*n+k] < 0 {
+			nrm = -nrm
+		}
+		for i := k; i < m; i++ {
+			qr[i*n+k] /= nrm
+		}
+		qr[k*n+k] += 1
+		// apply transformation to remaining columns
+		for j := k + 1; j < n; j++ {
+			var s float64 = 0
+			for i := k; i < m; i++ {
+				s += qr[i*n+k] * qr[i*n+j]
+			}
+			s = -s / qr[k*n+k]
+			for i := k; i < m; i++ {
+				qr[i*n+j] += s * qr[i*n+k]
+			}
+		}
+		f.rdiag[k] = -nrm
+	}
+	return
+}
+
+// Q returns the rows x cols matrix with orthonormal columns.
+func (f *QR) Q() (q *Matrix) {
+	m := f.qr.rows
+	n := f.qr.cols
+	qr := f.qr.entries
+	q, _ = ZeroMat(m, n)
+	// accumulate the Householder reflections backwards
+	for k := n - 1; k >= 0; k-- {
+		q.entries[k*n+k] = 1
+		if qr[k*n+k] == 0 {
+			continue
+		}
+		for j := k; j < n; j++ {
+			var s float64 = 0
+			for i := k; i < m; i++ {
+				s += qr[i*n+k] * q.entries[i*n+j]
+			}
+			s = -s / qr[k*n+k]
+			for i := k; i < m; i++ {
+				q.entries[i*n+j] += s * qr[i*n+k]
+			}
+		}
+	}
+	return
+}
+
+// R returns the cols x cols upper triangular factor.
+func (f *QR) R() (r *Matrix) {
+	n := f.qr.cols
+	r, _ = ZeroMat(n, n)
+	for i := 0; i < n; i++ {
+		r.entries[i*n+i] = f.rdiag[i]
+		for j := i + 1; j < n; j++ {
+			r.entries[i*n+j] = f.qr.entries[i*n+j]
+		}
+	}
+	return
+}
+
+// Rank returns the numerical rank of the factorized matrix,
+// i.e. the number of diagonal entries of R above a tolerance
+// relative to the largest one.
+func (f *QR) Rank() (rank int) {
+	var maxDiag float64 = 0
+	for _, d := range f.rdiag {
+		maxDiag = math.Max(maxDiag, math.Abs(d))
+	}
+	tol := float64(f.qr.rows) * maxDiag * eps
+	for _, d := range f.rdiag {
+		if math.Abs(d) > tol {
+			rank++
+		}
+	}
+	return
+}
+
+// Solve returns the least squares solution x, which minimizes ||A * x - b||.
+// Returns an error, if the sizes don't match or A is rank deficient.
+func (f *QR) Solve(b *Vector) (x *Vector, e error) {
+	// check sizes
+	if b.Size() != f.qr.rows {
+		e = &DimensionError{Op: "Solve", A: f.qr.shape(), B: b.shape()}
+		return
+	}
+	if f.Rank() < f.qr.cols {
+		e = ErrSingular
+		return
+	}
+	x = f.solve(b, f.qr.cols)
+	return
+}
+
+// solve returns the least squares solution, where only the leading
+// rank columns of R are used and the remaining unknowns are set to zero.
+func (f *QR) solve(b *Vector, rank int) (x *Vector) {
+	m := f.qr.rows
+	n := f.qr.cols
+	qr := f.qr.entries
+	// compute y = Q^T * b
+	y := b.Slice()
+	for k := 0; k < n; k++ {
+		if qr[k*n+k] == 0 {
+			continue
+		}
+		var s float64 = 0
+		for i := k; i < m; i++ {
+			s += qr[i*n+k] * y[i]
+		}
+		s = -s / qr[k*n+k]
+		for i := k; i < m; i++ {
+			y[i] += s * qr[i*n+k]
+		}
+	}
+	// solve R11 * z = y[0:rank] by backward substitution
+	z := make([]float64, n)
+	for i := rank - 1; i >= 0; i-- {
+		sum := y[i]
+		for j := i + 1; j < rank; j++ {
+			sum -= qr[i*n+j] * z[j]
+		}
+		z[i] = sum / f.rdiag[i]
+	}
+	// undo column permutation
+	x = ZeroVec(n)
+	for j := 0; j < n; j++ {
+		x.entries[f.perm[j]] = z[j]
+	}
+	return
+}
+
+// LeastSquares returns the vector x which minimizes ||a * x - b||,
+// the residual norm ||a * x - b|| and the numerical rank of a.
+// a needs at least as many rows as columns. For rank deficient
+// matrices a basic solution with rank non-zero entries is returned.
+func LeastSquares(a Mat, b *Vector) (x *Vector, residual float64, rank int, e error) {
+	x, residual, rank, e = leastSquares(asMatrix(a), b)
+	return
+}
+
+// leastSquares implements LeastSquares for a dense matrix
+func leastSquares(a *Matrix, b *Vector) (x *Vector, residual float64, rank int, e error) {
+	// check sizes
+	if a.rows != b.Size() {
+		e = &DimensionError{Op: "LeastSquares", A: a.shape(), B: b.shape()}
+		return
+	}
+	// factorize with column pivoting to reveal the rank
+	f, e := factorQR(a, true)
+	if e != nil {
+		return
+	}
+	rank = f.Rank()
+	x = f.solve(b, rank)
+	// compute residual norm
+	r := a.MulVec(x).Sub(b)
+	for _, ri := range r.entries {
+		residual = math.Hypot(residual, ri)
+	}
+	return
+}
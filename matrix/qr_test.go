@@ -0,0 +1,83 @@
+package matrix
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestQR(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for _, dims := range [][2]int{{1, 1}, {4, 4}, {8, 4}, {20, 3}} {
+		m, n := dims[0], dims[1]
+		a := randMat(m, n, rng)
+		f, e := NewQR(a)
+		if e != nil {
+			t.Fatal(dims, e)
+		}
+		q, r := f.Q(), f.R()
+		matClose(t, "QR", q.Mul(r), a, 1e-12)
+		id, _ := IdMat(n, n)
+		matClose(t, "Q^T Q", q.TMul(q), id, 1e-12)
+		for i := 1; i < n; i++ {
+			for j := 0; j < i; j++ {
+				if r.Get(i, j) != 0 {
+					t.Fatal("R not upper triangular")
+				}
+			}
+		}
+	}
+}
+
+func TestLeastSquares(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	a := randMat(8, 4, rng)
+	b := randVec(8, rng)
+	// solution of the normal equations
+	xn, _ := a.TMul(a).Solve(a.TMulVec(b))
+	f, _ := NewQR(a)
+	x, e := f.Solve(b)
+	if e != nil {
+		t.Fatal(e)
+	}
+	vecClose(t, "QR.Solve", x, xn, 1e-10)
+	x, res, rank, e := LeastSquares(a, b)
+	if e != nil || rank != 4 {
+		t.Fatal(e, rank)
+	}
+	vecClose(t, "LeastSquares", x, xn, 1e-10)
+	r := a.MulVec(x).Sub(b)
+	if math.Abs(res-math.Sqrt(r.Dot(r))) > 1e-12 {
+		t.Fatal(res)
+	}
+	// exact fit
+	xe := randVec(4, rng)
+	x, res, _, _ = LeastSquares(a, a.MulVec(xe))
+	vecClose(t, "exact", x, xe, 1e-10)
+	if res > 1e-10 {
+		t.Fatal(res)
+	}
+}
+
+func TestLeastSquaresRankDeficient(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	a := randMat(8, 2, rng)
+	d, _ := ZeroMat(8, 3)
+	d.SetCol(0, a.GetCol(0))
+	d.SetCol(1, a.GetCol(1))
+	d.SetCol(2, a.GetCol(0).Scale(2))
+	b := randVec(8, rng)
+	x, res, rank, e := LeastSquares(d, b)
+	if e != nil || rank != 2 {
+		t.Fatal(e, rank)
+	}
+	// the basic solution is as good as the one of the full rank columns
+	_, res2, _, _ := LeastSquares(a, b)
+	if math.Abs(res-res2) > 1e-10 || x.Size() != 3 {
+		t.Fatal(res, res2)
+	}
+	f, _ := NewQR(d)
+	if _, e := f.Solve(b); e == nil {
+		t.Fatal("expected error for rank deficient matrix")
+	}
+}
@@ -0,0 +1,819 @@
+/*	This file implements the sparse matrix types COO, CSR and CSC
+	and their conversions to and from the dense Matrix type
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// NewCOO creates an empty sparse r x c matrix in coordinate format.
+func NewCOO(r, c int) (m *COO, e error) {
+	if r <= 0 || c <= 0 {
+		e = fmt.Errorf("%w: invalid dimensions %dx%d", ErrInvalidArgument, r, c)
+		return
+	}
+	m = new(COO)
+	m.rows = r
+	m.cols = c
+	return
+}
+
+// COOFromTriplets creates a sparse r x c matrix with the entries
+// values[k] at (rowIdx[k], colIdx[k]). Duplicate entries are summed up.
+// Returns an error, if the slices have different lengths or contain
+// invalid indices.
+func COOFromTriplets(r, c int, rowIdx, colIdx []int, values []float64) (m *COO, e error) {
+	m, e = NewCOO(r, c)
+	if e != nil {
+		return
+	}
+	// check lengths
+	if len(rowIdx) != len(values) || len(colIdx) != len(values) {
+		m = nil
+		e = &DimensionError{Op: "COOFromTriplets", A: []int{len(rowIdx), len(colIdx)}, B: []int{len(values)}}
+		return
+	}
+	// check indices
+	for k := range values {
+		if rowIdx[k] < 0 || rowIdx[k] >= r || colIdx[k] < 0 || colIdx[k] >= c {
+			m = nil
+			e = &IndexError{Op: "COOFromTriplets", Index: []int{rowIdx[k], colIdx[k]}, Dims: []int{r, c}}
+			return
+		}
+	}
+	m.rowIdx = append([]int(nil), rowIdx...)
+	m.colIdx = append([]int(nil), colIdx...)
+	m.values = append([]float64(nil), values...)
+	return
+}
+
+// COOFromDense creates a sparse matrix with the non-zero entries of a.
+func COOFromDense(a *Matrix) (m *COO) {
+	m, _ = NewCOO(a.rows, a.cols)
+	for i := 0; i < a.rows; i++ {
+		for j := 0; j < a.cols; j++ {
+			if v := a.getEntry(i, j); v != 0 {
+				m.rowIdx = append(m.rowIdx, i)
+				m.colIdx = append(m.colIdx, j)
+				m.values = append(m.values, v)
+			}
+		}
+	}
+	return
+}
+
+// CSRFromDense creates a sparse row matrix with the non-zero entries of a.
+func CSRFromDense(a *Matrix) (m *CSR) {
+	m = COOFromDense(a).ToCSR()
+	return
+}
+
+// CSCFromDense creates a sparse column matrix with the non-zero entries of a.
+func CSCFromDense(a *Matrix) (m *CSC) {
+	m = COOFromDense(a).ToCSC()
+	return
+}
+
+// Rows returns the number of rows of the matrix
+func (m *COO) Rows() int {
+	return m.rows
+}
+
+// Cols returns the number of cols of the matrix
+func (m *COO) Cols() int {
+	return m.cols
+}
+
+// Dims returns the number of rows and cols of the matrix
+func (m *COO) Dims() (r, c int) {
+	return m.rows, m.cols
+}
+
+// At(i,j) returns the element in row i and column j like Get,
+// so that COO implements the Mat interface.
+func (m *COO) At(i, j int) float64 {
+	return m.Get(i, j)
+}
+
+// NNZ returns the number of stored entries (including duplicates).
+func (m *COO) NNZ() int {
+	return len(m.values)
+}
+
+// Append adds v to the entry in row i and column j.
+// Doesn't update, if invalid indices
+func (m *COO) Append(i, j int, v float64) {
+	m.AppendSafe(i, j, v)
+}
+
+// AppendSafe adds v to the entry in row i and column j.
+// Returns an IndexError, if invalid indices
+func (m *COO) AppendSafe(i, j int, v float64) (e error) {
+	// check indices
+	if i < 0 || j < 0 || i >= m.rows || j >= m.cols {
+		e = &IndexError{Op: "Append", Index: []int{i, j}, Dims: m.shape()}
+		return
+	}
+	m.rowIdx = append(m.rowIdx, i)
+	m.colIdx = append(m.colIdx, j)
+	m.values = append(m.values, v)
+	return
+}
+
+// Get(i,j) returns the element in row i and column j.
+// returns NaN if invalid index
+func (m *COO) Get(i, j int) float64 {
+	v, e := m.GetSafe(i, j)
+	if e != nil {
+		return math.NaN()
+	}
+	return v
+}
+
+// GetSafe(i,j) returns the element in row i and column j and an error value.
+// Returns an IndexError, if invalid indices
+func (m *COO) GetSafe(i, j int) (elem float64, e error) {
+	// check indices
+	if i < 0 || j < 0 || i >= m.rows || j >= m.cols {
+		e = &IndexError{Op: "Get", Index: []int{i, j}, Dims: m.shape()}
+		return
+	}
+	// sum up duplicates
+	for k := range m.values {
+		if m.rowIdx[k] == i && m.colIdx[k] == j {
+			elem += m.values[k]
+		}
+	}
+	return
+}
+
+// Set(i,j, v) sets the entry of row i and col j to value v.
+// No update, if invalid indices
+func (m *COO) Set(i, j int, v float64) {
+	m.SetSafe(i, j, v)
+}
+
+// SetSafe sets the entry of row i and col j to value v.
+// Returns an IndexError, if invalid indices
+func (m *COO) SetSafe(i, j int, v float64) (e error) {
+	// check indices
+	if i < 0 || j < 0 || i >= m.rows || j >= m.cols {
+		e = &IndexError{Op: "Set", Index: []int{i, j}, Dims: m.shape()}
+		return
+	}
+	// remove all stored entries at (i,j)
+	n := 0
+	for k := range m.values {
+		if m.rowIdx[k] == i && m.colIdx[k] == j {
+			continue
+		}
+		m.rowIdx[n] = m.rowIdx[k]
+		m.colIdx[n] = m.colIdx[k]
+		m.values[n] = m.values[k]
+		n++
+	}
+	m.rowIdx = m.rowIdx[:n]
+	m.colIdx = m.colIdx[:n]
+	m.values = m.values[:n]
+	if v != 0 {
+		e = m.AppendSafe(i, j, v)
+	}
+	return
+}
+
+// ToDense returns a dense matrix with the entries of m.
+func (m *COO) ToDense() (a *Matrix) {
+	a, _ = ZeroMat(m.rows, m.cols)
+	for k := range m.values {
+		a.entries[m.rowIdx[k]*m.cols+m.colIdx[k]] += m.values[k]
+	}
+	return
+}
+
+// ToCSR returns the matrix in compressed sparse row format.
+// Duplicate entries are summed up.
+func (m *COO) ToCSR() (s *CSR) {
+	s = new(CSR)
+	s.compressed = compress(m.rows, m.cols, m.rowIdx, m.colIdx, m.values)
+	return
+}
+
+// ToCSC returns the matrix in compressed sparse column format.
+// Duplicate entries are summed up.
+func (m *COO) ToCSC() (s *CSC) {
+	s = new(CSC)
+	s.compressed = compress(m.cols, m.rows, m.colIdx, m.rowIdx, m.values)
+	return
+}
+
+// T returns the transpose of m.
+func (m *COO) T() (t *COO) {
+	t, _ = NewCOO(m.cols, m.rows)
+	t.rowIdx = append([]int(nil), m.colIdx...)
+	t.colIdx = append([]int(nil), m.rowIdx...)
+	t.values = append([]float64(nil), m.values...)
+	return
+}
+
+// Rows returns the number of rows of the matrix
+func (m *CSR) Rows() int {
+	return m.major
+}
+
+// Cols returns the number of cols of the matrix
+func (m *CSR) Cols() int {
+	return m.minor
+}
+
+// Dims returns the number of rows and cols of the matrix
+func (m *CSR) Dims() (r, c int) {
+	return m.major, m.minor
+}
+
+// At(i,j) returns the element in row i and column j like Get,
+// so that CSR implements the Mat interface.
+func (m *CSR) At(i, j int) float64 {
+	return m.Get(i, j)
+}
+
+// Get(i,j) returns the element in row i and column j.
+// returns NaN if invalid index
+func (m *CSR) Get(i, j int) float64 {
+	v, e := m.GetSafe(i, j)
+	if e != nil {
+		return math.NaN()
+	}
+	return v
+}
+
+// GetSafe(i,j) returns the element in row i and column j and an error value.
+// Returns an IndexError, if invalid indices
+func (m *CSR) GetSafe(i, j int) (elem float64, e error) {
+	// check indices
+	if i < 0 || j < 0 || i >= m.major || j >= m.minor {
+		e = &IndexError{Op: "Get", Index: []int{i, j}, Dims: []int{m.major, m.minor}}
+		return
+	}
+	elem = m.get(i, j)
+	return
+}
+
+// Set(i,j, v) sets the entry of row i and col j to value v.
+// No update, if invalid indices
+func (m *CSR) Set(i, j int, v float64) {
+	m.SetSafe(i, j, v)
+}
+
+// SetSafe sets the entry of row i and col j to value v.
+// Inserting a new entry moves all entries in the following rows.
+// Returns an IndexError, if invalid indices
+func (m *CSR) SetSafe(i, j int, v float64) (e error) {
+	// check indices
+	if i < 0 || j < 0 || i >= m.major || j >= m.minor {
+		e = &IndexError{Op: "Set", Index: []int{i, j}, Dims: []int{m.major, m.minor}}
+		return
+	}
+	m.set(i, j, v)
+	return
+}
+
+// ToDense returns a dense matrix with the entries of m.
+func (m *CSR) ToDense() (a *Matrix) {
+	a, _ = ZeroMat(m.major, m.minor)
+	for i := 0; i < m.major; i++ {
+		for k := m.indptr[i]; k < m.indptr[i+1]; k++ {
+			a.entries[i*m.minor+m.indices[k]] = m.values[k]
+		}
+	}
+	return
+}
+
+// ToCOO returns the matrix in coordinate format.
+func (m *CSR) ToCOO() (c *COO) {
+	c, _ = NewCOO(m.major, m.minor)
+	c.rowIdx, c.colIdx, c.values = m.triplets()
+	return
+}
+
+// ToCSC returns the matrix in compressed sparse column format.
+func (m *CSR) ToCSC() (s *CSC) {
+	s = new(CSC)
+	s.compressed = m.transposed()
+	return
+}
+
+// T returns the transpose of m. The rows of m are the columns of
+// its transpose, so the storage is copied without sorting.
+func (m *CSR) T() (t *CSC) {
+	t = new(CSC)
+	t.compressed = m.copy()
+	return
+}
+
+// MulVec computes the matrix-vector product w = m * v.
+// Dimension mismatch (m.Cols() != v.Size()) returns nil.
+func (m *CSR) MulVec(v *Vector) (w *Vector) {
+	w, _ = m.MulVecSafe(v)
+	return
+}
+
+// MulVecSafe computes the matrix-vector product w = m * v.
+// Returns a DimensionError, if m.Cols() != v.Size().
+func (m *CSR) MulVecSafe(v *Vector) (w *Vector, e error) {
+	// check if dimensions match
+	if m.minor != v.Size() {
+		e = &DimensionError{Op: "MulVec", A: []int{m.major, m.minor}, B: v.shape()}
+		return
+	}
+	w = m.gatherMulVec(v)
+	return
+}
+
+// TMulVec computes the matrix-vector product w = m^T * v.
+// Dimension mismatch (m.Rows() != v.Size()) returns nil.
+func (m *CSR) TMulVec(v *Vector) (w *Vector) {
+	w, _ = m.TMulVecSafe(v)
+	return
+}
+
+// TMulVecSafe computes the matrix-vector product w = m^T * v.
+// Returns a DimensionError, if m.Rows() != v.Size().
+func (m *CSR) TMulVecSafe(v *Vector) (w *Vector, e error) {
+	// check if dimensions match
+	if m.major != v.Size() {
+		e = &DimensionError{Op: "TMulVec", A: []int{m.major, m.minor}, B: v.shape()}
+		return
+	}
+	w = m.scatterMulVec(v)
+	return
+}
+
+// Mul computes the sparse-dense product c = m * b.
+// Dimension mismatch (m.Cols() != b.Rows()) returns nil.
+func (m *CSR) Mul(b *Matrix) (c *Matrix) {
+	c, _ = m.MulSafe(b)
+	return
+}
+
+// MulSafe computes the sparse-dense product c = m * b.
+// Returns a DimensionError, if m.Cols() != b.Rows().
+func (m *CSR) MulSafe(b *Matrix) (c *Matrix, e error) {
+	// check if dimensions match
+	if m.minor != b.rows {
+		e = &DimensionError{Op: "Mul", A: []int{m.major, m.minor}, B: b.shape()}
+		return
+	}
+	c = m.gatherMul(b)
+	return
+}
+
+// MulCSR computes the sparse-sparse product c = m * b.
+// Dimension mismatch (m.Cols() != b.Rows()) returns nil.
+func (m *CSR) MulCSR(b *CSR) (c *CSR) {
+	c, _ = m.MulCSRSafe(b)
+	return
+}
+
+// MulCSRSafe computes the sparse-sparse product c = m * b.
+// Returns a DimensionError, if m.Cols() != b.Rows().
+func (m *CSR) MulCSRSafe(b *CSR) (c *CSR, e error) {
+	// check if dimensions match
+	if m.minor != b.major {
+		e = &DimensionError{Op: "MulCSR", A: []int{m.major, m.minor}, B: []int{b.major, b.minor}}
+		return
+	}
+	c = new(CSR)
+	c.compressed = m.mulCompressed(&b.compressed)
+	return
+}
+
+// Rows returns the number of rows of the matrix
+func (m *CSC) Rows() int {
+	return m.minor
+}
+
+// Cols returns the number of cols of the matrix
+func (m *CSC) Cols() int {
+	return m.major
+}
+
+// Dims returns the number of rows and cols of the matrix
+func (m *CSC) Dims() (r, c int) {
+	return m.minor, m.major
+}
+
+// At(i,j) returns the element in row i and column j like Get,
+// so that CSC implements the Mat interface.
+func (m *CSC) At(i, j int) float64 {
+	return m.Get(i, j)
+}
+
+// Get(i,j) returns the element in row i and column j.
+// returns NaN if invalid index
+func (m *CSC) Get(i, j int) float64 {
+	v, e := m.GetSafe(i, j)
+	if e != nil {
+		return math.NaN()
+	}
+	return v
+}
+
+// GetSafe(i,j) returns the element in row i and column j and an error value.
+// Returns an IndexError, if invalid indices
+func (m *CSC) GetSafe(i, j int) (elem float64, e error) {
+	// check indices
+	if i < 0 || j < 0 || i >= m.minor || j >= m.major {
+		e = &IndexError{Op: "Get", Index: []int{i, j}, Dims: []int{m.minor, m.major}}
+		return
+	}
+	elem = m.get(j, i)
+	return
+}
+
+// Set(i,j, v) sets the entry of row i and col j to value v.
+// No update, if invalid indices
+func (m *CSC) Set(i, j int, v float64) {
+	m.SetSafe(i, j, v)
+}
+
+// SetSafe sets the entry of row i and col j to value v.
+// Inserting a new entry moves all entries in the following columns.
+// Returns an IndexError, if invalid indices
+func (m *CSC) SetSafe(i, j int, v float64) (e error) {
+	// check indices
+	if i < 0 || j < 0 || i >= m.minor || j >= m.major {
+		e = &IndexError{Op: "Set", Index: []int{i, j}, Dims: []int{m.minor, m.major}}
+		return
+	}
+	m.set(j, i, v)
+	return
+}
+
+// ToDense returns a dense matrix with the entries of m.
+func (m *CSC) ToDense() (a *Matrix) {
+	a, _ = ZeroMat(m.minor, m.major)
+	for j := 0; j < m.major; j++ {
+		for k := m.indptr[j]; k < m.indptr[j+1]; k++ {
+			a.entries[m.indices[k]*m.major+j] = m.values[k]
+		}
+	}
+	return
+}
+
+// ToCOO returns the matrix in coordinate format.
+func (m *CSC) ToCOO() (c *COO) {
+	c, _ = NewCOO(m.minor, m.major)
+	c.colIdx, c.rowIdx, c.values = m.triplets()
+	return
+}
+
+// ToCSR returns the matrix in compressed sparse row format.
+func (m *CSC) ToCSR() (s *CSR) {
+	s = new(CSR)
+	s.compressed = m.transposed()
+	return
+}
+
+// T returns the transpose of m. The columns of m are the rows of
+// its transpose, so the storage is copied without sorting.
+func (m *CSC) T() (t *CSR) {
+	t = new(CSR)
+	t.compressed = m.copy()
+	return
+}
+
+// MulVec computes the matrix-vector product w = m * v.
+// Dimension mismatch (m.Cols() != v.Size()) returns nil.
+func (m *CSC) MulVec(v *Vector) (w *Vector) {
+	w, _ = m.MulVecSafe(v)
+	return
+}
+
+// MulVecSafe computes the matrix-vector product w = m * v.
+// Returns a DimensionError, if m.Cols() != v.Size().
+func (m *CSC) MulVecSafe(v *Vector) (w *Vector, e error) {
+	// check if dimensions match
+	if m.major != v.Size() {
+		e = &DimensionError{Op: "MulVec", A: []int{m.minor, m.major}, B: v.shape()}
+		return
+	}
+	w = m.scatterMulVec(v)
+	return
+}
+
+// TMulVec computes the matrix-vector product w = m^T * v.
+// Dimension mismatch (m.Rows() != v.Size()) returns nil.
+func (m *CSC) TMulVec(v *Vector) (w *Vector) {
+	w, _ = m.TMulVecSafe(v)
+	return
+}
+
+// TMulVecSafe computes the matrix-vector product w = m^T * v.
+// Returns a DimensionError, if m.Rows() != v.Size().
+func (m *CSC) TMulVecSafe(v *Vector) (w *Vector, e error) {
+	// check if dimensions match
+	if m.minor != v.Size() {
+		e = &DimensionError{Op: "TMulVec", A: []int{m.minor, m.major}, B: v.shape()}
+		return
+	}
+	w = m.gatherMulVec(v)
+	return
+}
+
+// Mul computes the sparse-dense product c = m * b.
+// Dimension mismatch (m.Cols() != b.Rows()) returns nil.
+func (m *CSC) Mul(b *Matrix) (c *Matrix) {
+	c, _ = m.MulSafe(b)
+	return
+}
+
+// MulSafe computes the sparse-dense product c = m * b.
+// Returns a DimensionError, if m.Cols() != b.Rows().
+func (m *CSC) MulSafe(b *Matrix) (c *Matrix, e error) {
+	// check if dimensions match
+	if m.major != b.rows {
+		e = &DimensionError{Op: "Mul", A: []int{m.minor, m.major}, B: b.shape()}
+		return
+	}
+	c = m.scatterMul(b)
+	return
+}
+
+// MulCSC computes the sparse-sparse product c = m * b.
+// Dimension mismatch (m.Cols() != b.Rows()) returns nil.
+func (m *CSC) MulCSC(b *CSC) (c *CSC) {
+	c, _ = m.MulCSCSafe(b)
+	return
+}
+
+// MulCSCSafe computes the sparse-sparse product c = m * b.
+// Returns a DimensionError, if m.Cols() != b.Rows().
+func (m *CSC) MulCSCSafe(b *CSC) (c *CSC, e error) {
+	// check if dimensions match
+	if m.major != b.minor {
+		e = &DimensionError{Op: "MulCSC", A: []int{m.minor, m.major}, B: []int{b.minor, b.major}}
+		return
+	}
+	// (m * b)^T = b^T * m^T, where the transposes are rows of CSR
+	c = new(CSC)
+	c.compressed = b.mulCompressed(&m.compressed)
+	return
+}
+
+// Diag returns a copy of the main diagonal of m.
+func (m *compressed) Diag() (d *Vector) {
+	d = ZeroVec(min(m.major, m.minor))
+	for k := range d.entries {
+		d.entries[k] = m.get(k, k)
+	}
+	return
+}
+
+// NNZ returns the number of stored entries.
+func (m *compressed) NNZ() int {
+	return len(m.values)
+}
+
+// compress returns the compressed storage of the given triplets,
+// where duplicates are summed up.
+func compress(major, minor int, majorIdx, minorIdx []int, values []float64) (c compressed) {
+	c.major = major
+	c.minor = minor
+	// count entries per major index
+	c.indptr = make([]int, major+1)
+	for _, k := range majorIdx {
+		c.indptr[k+1]++
+	}
+	for k := 0; k < major; k++ {
+		c.indptr[k+1] += c.indptr[k]
+	}
+	// scatter entries
+	pos := append([]int(nil), c.indptr[:major]...)
+	indices := make([]int, len(values))
+	vals := make([]float64, len(values))
+	for k, v := range values {
+		p := pos[majorIdx[k]]
+		indices[p] = minorIdx[k]
+		vals[p] = v
+		pos[majorIdx[k]]++
+	}
+	// sort every major index and sum up duplicates
+	c.indices = indices[:0]
+	c.values = vals[:0]
+	start := 0
+	for k := 0; k < major; k++ {
+		end := c.indptr[k+1]
+		sort.Sort(byIndex{indices[start:end], vals[start:end]})
+		c.indptr[k] = len(c.indices)
+		for p := start; p < end; p++ {
+			n := len(c.indices)
+			if n > c.indptr[k] && c.indices[n-1] == indices[p] {
+				c.values[n-1] += vals[p]
+				continue
+			}
+			c.indices = append(c.indices, indices[p])
+			c.values = append(c.values, vals[p])
+		}
+		start = end
+	}
+	c.indptr[major] = len(c.indices)
+	return
+}
+
+// byIndex sorts indices and values by index
+type byIndex struct {
+	indices []int
+	values  []float64
+}
+
+func (s byIndex) Len() int           { return len(s.indices) }
+func (s byIndex) Less(i, j int) bool { return s.indices[i] < s.indices[j] }
+func (s byIndex) Swap(i, j int) {
+	s.indices[i], s.indices[j] = s.indices[j], s.indices[i]
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+}
+
+// find returns the position of (k, l) in indices and values
+// and whether the entry is stored.
+func (m *compressed) find(k, l int) (p int, ok bool) {
+	start := m.indptr[k]
+	end := m.indptr[k+1]
+	p = start + sort.SearchInts(m.indices[start:end], l)
+	ok = p < end && m.indices[p] == l
+	return
+}
+
+// get returns the entry at major index k and minor index l.
+func (m *compressed) get(k, l int) float64 {
+	p, ok := m.find(k, l)
+	if !ok {
+		return 0
+	}
+	return m.values[p]
+}
+
+// set sets the entry at major index k and minor index l.
+// New zero entries are not stored.
+func (m *compressed) set(k, l int, v float64) {
+	p, ok := m.find(k, l)
+	if ok {
+		m.values[p] = v
+		return
+	}
+	if v == 0 {
+		return
+	}
+	// insert at position p
+	m.indices = append(m.indices, 0)
+	m.values = append(m.values, 0)
+	copy(m.indices[p+1:], m.indices[p:])
+	copy(m.values[p+1:], m.values[p:])
+	m.indices[p] = l
+	m.values[p] = v
+	for q := k + 1; q <= m.major; q++ {
+		m.indptr[q]++
+	}
+}
+
+// triplets returns the stored entries as (major, minor, value) triplets.
+func (m *compressed) triplets() (majorIdx, minorIdx []int, values []float64) {
+	majorIdx = make([]int, len(m.values))
+	for k := 0; k < m.major; k++ {
+		for p := m.indptr[k]; p < m.indptr[k+1]; p++ {
+			majorIdx[p] = k
+		}
+	}
+	minorIdx = append([]int(nil), m.indices...)
+	values = append([]float64(nil), m.values...)
+	return
+}
+
+// copy returns a copy of the storage, which shares no slices with m.
+func (m *compressed) copy() (c compressed) {
+	c = *m
+	c.indptr = append([]int(nil), m.indptr...)
+	c.indices = append([]int(nil), m.indices...)
+	c.values = append([]float64(nil), m.values...)
+	return
+}
+
+// transposed returns the storage with major and minor index swapped.
+func (m *compressed) transposed() (t compressed) {
+	majorIdx, minorIdx, values := m.triplets()
+	t = compress(m.minor, m.major, minorIdx, majorIdx, values)
+	return
+}
+
+// gatherMulVec computes w[k] = sum_l m(k,l) * v[l].
+func (m *compressed) gatherMulVec(v *Vector) (w *Vector) {
+	w = ZeroVec(m.major)
+	for k := 0; k < m.major; k++ {
+		var sum float64 = 0
+		for p := m.indptr[k]; p < m.indptr[k+1]; p++ {
+			sum += m.values[p] * v.at(m.indices[p])
+		}
+		w.entries[k] = sum
+	}
+	return
+}
+
+// scatterMulVec computes w[l] = sum_k m(k,l) * v[k].
+func (m *compressed) scatterMulVec(v *Vector) (w *Vector) {
+	w = ZeroVec(m.minor)
+	for k := 0; k < m.major; k++ {
+		vk := v.at(k)
+		if vk == 0 {
+			continue
+		}
+		for p := m.indptr[k]; p < m.indptr[k+1]; p++ {
+			w.entries[m.indices[p]] += m.values[p] * vk
+		}
+	}
+	return
+}
+
+// gatherMul computes c[k,:] = sum_l m(k,l) * b[l,:].
+func (m *compressed) gatherMul(b *Matrix) (c *Matrix) {
+	c, _ = ZeroMat(m.major, b.cols)
+	// the inner loop needs the rows of b as slices
+	if b.cstride != 1 {
+		b = b.CopyMat()
+	}
+	for k := 0; k < m.major; k++ {
+		cRow := c.row(k)
+		for p := m.indptr[k]; p < m.indptr[k+1]; p++ {
+			val := m.values[p]
+			bRow := b.row(m.indices[p])
+			for j := range cRow {
+				cRow[j] += val * bRow[j]
+			}
+		}
+	}
+	return
+}
+
+// scatterMul computes c[l,:] = sum_k m(k,l) * b[k,:].
+func (m *compressed) scatterMul(b *Matrix) (c *Matrix) {
+	c, _ = ZeroMat(m.minor, b.cols)
+	// the inner loop needs the rows of b as slices
+	if b.cstride != 1 {
+		b = b.CopyMat()
+	}
+	for k := 0; k < m.major; k++ {
+		bRow := b.row(k)
+		for p := m.indptr[k]; p < m.indptr[k+1]; p++ {
+			val := m.values[p]
+			cRow := c.row(m.indices[p])
+			for j := range cRow {
+				cRow[j] += val * bRow[j]
+			}
+		}
+	}
+	return
+}
+
+// mulCompressed computes the product of m and b, both interpreted
+// with major index as row index (Gustavson's algorithm).
+func (m *compressed) mulCompressed(b *compressed) (c compressed) {
+	c.major = m.major
+	c.minor = b.minor
+	c.indptr = make([]int, m.major+1)
+	// dense accumulator for one row of the result
+	acc := make([]float64, b.minor)
+	used := make([]bool, b.minor)
+	var cols []int
+	for k := 0; k < m.major; k++ {
+		cols = cols[:0]
+		for p := m.indptr[k]; p < m.indptr[k+1]; p++ {
+			l := m.indices[p]
+			val := m.values[p]
+			for q := b.indptr[l]; q < b.indptr[l+1]; q++ {
+				j := b.indices[q]
+				if !used[j] {
+					used[j] = true
+					cols = append(cols, j)
+				}
+				acc[j] += val * b.values[q]
+			}
+		}
+		sort.Ints(cols)
+		for _, j := range cols {
+			c.indices = append(c.indices, j)
+			c.values = append(c.values, acc[j])
+			acc[j] = 0
+			used[j] = false
+		}
+		c.indptr[k+1] = len(c.indices)
+	}
+	return
+}
+
+// shape returns the dimensions of m for error values
+func (m *COO) shape() []int {
+	return []int{m.rows, m.cols}
+}
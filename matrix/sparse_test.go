@@ -0,0 +1,150 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// sparseMat returns a random r x c matrix with about a third zero entries
+func sparseMat(r, c int, rng *rand.Rand) *Matrix {
+	a := randMat(r, c, rng)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if (i+2*j)%3 == 0 {
+				a.Set(i, j, 0)
+			}
+		}
+	}
+	return a
+}
+
+func TestSparseConversions(t *testing.T) {
+	rng := rand.New(rand.NewSource(12))
+	a := sparseMat(7, 5, rng)
+	csr := CSRFromDense(a)
+	csc := CSCFromDense(a)
+	coo := COOFromDense(a)
+	tests := []struct {
+		name      string
+		got, want *Matrix
+	}{
+		{"CSR", csr.ToDense(), a},
+		{"CSC", csc.ToDense(), a},
+		{"COO", coo.ToDense(), a},
+		{"CSR.ToCSC", csr.ToCSC().ToDense(), a},
+		{"CSC.ToCSR", csc.ToCSR().ToDense(), a},
+		{"CSR.ToCOO", csr.ToCOO().ToDense(), a},
+		{"CSC.ToCOO", csc.ToCOO().ToDense(), a},
+		{"CSR.T", csr.T().ToDense(), naiveT(a)},
+		{"CSC.T", csc.T().ToDense(), naiveT(a)},
+		{"COO.T", coo.T().ToDense(), naiveT(a)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matClose(t, tt.name, tt.got, tt.want, 0)
+		})
+	}
+}
+
+func TestSparseProducts(t *testing.T) {
+	rng := rand.New(rand.NewSource(12))
+	a := sparseMat(7, 5, rng)
+	b := randMat(5, 4, rng)
+	csr := CSRFromDense(a)
+	csc := CSCFromDense(a)
+	want := a.Mul(b)
+	matClose(t, "CSR.Mul", csr.Mul(b), want, 1e-12)
+	matClose(t, "CSC.Mul", csc.Mul(b), want, 1e-12)
+	matClose(t, "CSR.Mul strided", csr.Mul(b.T().CopyMat().T()), want, 1e-12)
+	matClose(t, "MulCSR", csr.MulCSR(CSRFromDense(b)).ToDense(), want, 1e-12)
+	matClose(t, "MulCSC", csc.MulCSC(CSCFromDense(b)).ToDense(), want, 1e-12)
+	v := randVec(5, rng)
+	vecClose(t, "CSR.MulVec", csr.MulVec(v), a.MulVec(v), 1e-12)
+	vecClose(t, "CSC.MulVec", csc.MulVec(v), a.MulVec(v), 1e-12)
+	u := randVec(7, rng)
+	vecClose(t, "CSR.TMulVec", csr.TMulVec(u), a.TMulVec(u), 1e-12)
+	vecClose(t, "CSC.TMulVec", csc.TMulVec(u), a.TMulVec(u), 1e-12)
+	if csr.Mul(randMat(3, 3, rng)) != nil || csr.MulVec(randVec(2, rng)) != nil {
+		t.Fatal("mismatched products should be nil")
+	}
+}
+
+func TestSparseSafe(t *testing.T) {
+	a, _ := MatrixFromSlice([][]float64{{1, 0, 2}, {0, 3, 0}})
+	r, c := CSRFromDense(a), CSCFromDense(a)
+	v3 := VecFromSlice([]float64{1, 1, 1})
+	var de *DimensionError
+	tests := []struct {
+		name string
+		e    error
+		op   string
+	}{
+		{"CSR.TMulVecSafe", second(r.TMulVecSafe(v3)), "TMulVec"},
+		{"CSC.TMulVecSafe", second(c.TMulVecSafe(v3)), "TMulVec"},
+		{"MulCSRSafe", second(r.MulCSRSafe(r)), "MulCSR"},
+		{"MulCSCSafe", second(c.MulCSCSafe(c)), "MulCSC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.As(tt.e, &de) || de.Op != tt.op || de.A[0] != 2 || de.A[1] != 3 {
+				t.Fatal(tt.e)
+			}
+		})
+	}
+	p, e := r.MulCSRSafe(r.T().ToCSR())
+	if e != nil {
+		t.Fatal(e)
+	}
+	matClose(t, "MulCSRSafe", p.ToDense(), a.MulT(a), 0)
+	q, e := c.MulCSCSafe(c.T().ToCSC())
+	if e != nil {
+		t.Fatal(e)
+	}
+	matClose(t, "MulCSCSafe", q.ToDense(), a.MulT(a), 0)
+}
+
+func TestSparseSet(t *testing.T) {
+	rng := rand.New(rand.NewSource(12))
+	a := sparseMat(7, 5, rng)
+	csr := CSRFromDense(a)
+	csc := CSCFromDense(a)
+	for _, s := range [][3]float64{{6, 4, 9}, {0, 0, 8}, {3, 2, 7}} {
+		i, j := int(s[0]), int(s[1])
+		csr.Set(i, j, s[2])
+		csc.Set(i, j, s[2])
+		a.Set(i, j, s[2])
+	}
+	matClose(t, "CSR.Set", csr.ToDense(), a, 0)
+	matClose(t, "CSC.Set", csc.ToDense(), a, 0)
+	if _, e := csr.GetSafe(7, 0); e == nil || !math.IsNaN(csc.Get(-1, 0)) {
+		t.Fatal("index out of range")
+	}
+	// the transpose has its own storage
+	d, _ := MatrixFromSlice([][]float64{{1, 0}, {0, 2}})
+	m := CSRFromDense(d)
+	m.T().Set(0, 1, 5)
+	matClose(t, "CSR.T copy", m.ToDense(), d, 0)
+	n := CSCFromDense(d)
+	n.T().Set(1, 0, 7)
+	matClose(t, "CSC.T copy", n.ToDense(), d, 0)
+}
+
+func TestCOO(t *testing.T) {
+	c, e := COOFromTriplets(2, 2, []int{0, 0, 1}, []int{1, 1, 0}, []float64{1, 2, 3})
+	if e != nil {
+		t.Fatal(e)
+	}
+	// duplicates are summed
+	if c.Get(0, 1) != 3 || c.ToCSR().Get(0, 1) != 3 || c.ToCSC().NNZ() != 2 {
+		t.Fatal("duplicates")
+	}
+	c.Set(0, 1, 5)
+	if c.Get(0, 1) != 5 || c.NNZ() != 2 {
+		t.Fatal("Set")
+	}
+	if _, e := COOFromTriplets(2, 2, []int{2}, []int{0}, []float64{1}); e == nil {
+		t.Fatal("expected index error")
+	}
+}
@@ -0,0 +1,367 @@
+/*	This file implements numerically stable descriptive statistics of the
+	elements of a vector
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// kahan is a compensated (Kahan-Babuska-Neumaier) sum
+type kahan struct {
+	sum float64
+	c   float64
+}
+
+// add adds x to the sum
+func (k *kahan) add(x float64) {
+	t := k.sum + x
+	// collect the lost low order bits
+	if math.Abs(k.sum) >= math.Abs(x) {
+		k.c += (k.sum - t) + x
+	} else {
+		k.c += (x - t) + k.sum
+	}
+	k.sum = t
+}
+
+// value returns the compensated sum
+func (k *kahan) value() float64 {
+	return k.sum + k.c
+}
+
+// moments accumulates the count, mean and central moments of a sample
+// with Welford's online algorithm, extended to the third and fourth moment
+type moments struct {
+	n    float64
+	mean float64
+	m2   float64
+	m3   float64
+	m4   float64
+}
+
+// add adds the observation x
+func (m *moments) add(x float64) {
+	n1 := m.n
+	m.n++
+	delta := x - m.mean
+	deltaN := delta / m.n
+	deltaN2 := deltaN * deltaN
+	term := delta * deltaN * n1
+	m.mean += deltaN
+	m.m4 += term*deltaN2*(m.n*m.n-3*m.n+3) + 6*deltaN2*m.m2 - 4*deltaN*m.m3
+	m.m3 += term*deltaN*(m.n-2) - 3*deltaN*m.m2
+	m.m2 += term
+}
+
+// merge adds the observations accumulated in o
+func (m *moments) merge(o *moments) {
+	if o.n == 0 {
+		return
+	}
+	if m.n == 0 {
+		*m = *o
+		return
+	}
+	n := m.n + o.n
+	delta := o.mean - m.mean
+	delta2 := delta * delta
+	mean := m.mean + delta*o.n/n
+	m2 := m.m2 + o.m2 + delta2*m.n*o.n/n
+	m3 := m.m3 + o.m3 + delta*delta2*m.n*o.n*(m.n-o.n)/(n*n) +
+		3*delta*(m.n*o.m2-o.n*m.m2)/n
+	m4 := m.m4 + o.m4 + delta2*delta2*m.n*o.n*(m.n*m.n-m.n*o.n+o.n*o.n)/(n*n*n) +
+		6*delta2*(m.n*m.n*o.m2+o.n*o.n*m.m2)/(n*n) + 4*delta*(m.n*o.m3-o.n*m.m3)/n
+	*m = moments{n: n, mean: mean, m2: m2, m3: m3, m4: m4}
+}
+
+// variance returns the sample variance, 0 for one and NaN for no observation
+func (m *moments) variance() float64 {
+	switch m.n {
+	case 0:
+		return math.NaN()
+	case 1:
+		return 0
+	}
+	return m.m2 / (m.n - 1)
+}
+
+// skewness returns the sample skewness m3 / m2^(3/2)
+func (m *moments) skewness() float64 {
+	if m.n == 0 || m.m2 == 0 {
+		return math.NaN()
+	}
+	return math.Sqrt(m.n) * m.m3 / math.Pow(m.m2, 1.5)
+}
+
+// kurtosis returns the sample excess kurtosis m4 / m2^2 - 3
+func (m *moments) kurtosis() float64 {
+	if m.n == 0 || m.m2 == 0 {
+		return math.NaN()
+	}
+	return m.n*m.m4/(m.m2*m.m2) - 3
+}
+
+// Sum returns the sum of the elements, computed with compensated summation.
+func (a *Vector) Sum() float64 {
+	var k kahan
+	for i := 0; i < a.Size(); i++ {
+		k.add(a.at(i))
+	}
+	return k.value()
+}
+
+// Std returns the sample standard deviation sqrt(Var()).
+// returns NaN for an empty vector
+func (a *Vector) Std() float64 {
+	return math.Sqrt(a.Var())
+}
+
+// StdSafe returns the sample standard deviation and an error value.
+// Returns ErrInvalidArgument and NaN for an empty vector
+func (a *Vector) StdSafe() (std float64, e error) {
+	variance, e := a.VarSafe()
+	std = math.Sqrt(variance)
+	return
+}
+
+// Skewness returns the sample skewness m3 / m2^(3/2), where mk is the
+// k-th central moment (biased estimator, as scipy.stats.skew).
+// returns NaN for an empty or constant vector
+func (a *Vector) Skewness() float64 {
+	m := a.moments()
+	return m.skewness()
+}
+
+// SkewnessSafe returns the sample skewness and an error value.
+// Returns ErrInvalidArgument and NaN for an empty vector,
+// NaN without error for a constant one
+func (a *Vector) SkewnessSafe() (skew float64, e error) {
+	// check size
+	if a.Size() == 0 {
+		skew = math.NaN()
+		e = fmt.Errorf("%w: skewness of an empty vector", ErrInvalidArgument)
+		return
+	}
+	skew = a.Skewness()
+	return
+}
+
+// Kurtosis returns the sample excess kurtosis m4 / m2^2 - 3, where mk is
+// the k-th central moment (biased estimator, as scipy.stats.kurtosis).
+// returns NaN for an empty or constant vector
+func (a *Vector) Kurtosis() float64 {
+	m := a.moments()
+	return m.kurtosis()
+}
+
+// KurtosisSafe returns the sample excess kurtosis and an error value.
+// Returns ErrInvalidArgument and NaN for an empty vector,
+// NaN without error for a constant one
+func (a *Vector) KurtosisSafe() (kurt float64, e error) {
+	// check size
+	if a.Size() == 0 {
+		kurt = math.NaN()
+		e = fmt.Errorf("%w: kurtosis of an empty vector", ErrInvalidArgument)
+		return
+	}
+	kurt = a.Kurtosis()
+	return
+}
+
+// Median returns the median of the elements.
+// returns NaN for an empty vector or if an element is NaN
+func (a *Vector) Median() float64 {
+	return a.Quantile(0.5, QuantileLinear)
+}
+
+// Quantile returns the p-quantile of the elements, interpolated with method.
+// returns NaN for an empty vector, if an element is NaN or p is not in [0,1]
+func (a *Vector) Quantile(p float64, method QuantileMethod) float64 {
+	return a.Quantiles([]float64{p}, method).at(0)
+}
+
+// Quantiles returns the quantiles of the elements for all ps,
+// sorting the elements only once. Invalid quantiles are NaN like in Quantile.
+func (a *Vector) Quantiles(ps []float64, method QuantileMethod) (q *Vector) {
+	q = ZeroVec(len(ps))
+	sorted := a.sorted()
+	for k, p := range ps {
+		q.entries[k] = quantileSorted(sorted, p, method)
+	}
+	return
+}
+
+// Mode returns the most frequent element and its count.
+// Ties are resolved by the smallest element, NaNs are ignored.
+// returns NaN and 0 for an empty vector
+func (a *Vector) Mode() (value float64, count int) {
+	value = math.NaN()
+	sorted := a.sorted()
+	// NaNs are sorted to the front
+	for len(sorted) > 0 && math.IsNaN(sorted[0]) {
+		sorted = sorted[1:]
+	}
+	for i := 0; i < len(sorted); {
+		j := i + 1
+		for j < len(sorted) && sorted[j] == sorted[i] {
+			j++
+		}
+		if j-i > count {
+			value = sorted[i]
+			count = j - i
+		}
+		i = j
+	}
+	return
+}
+
+// WeightedMean returns sum w_i * a_i / sum w_i.
+// returns NaN for mismatching sizes, negative weights or a zero weight sum
+func (a *Vector) WeightedMean(w *Vector) float64 {
+	if !validWeights(a, w) {
+		return math.NaN()
+	}
+	var sum, wsum kahan
+	for i := 0; i < a.Size(); i++ {
+		sum.add(w.at(i) * a.at(i))
+		wsum.add(w.at(i))
+	}
+	return sum.value() / wsum.value()
+}
+
+// WeightedVar returns the variance with frequency weights w,
+// i.e. sum w_i * (a_i - mean)^2 / (sum w_i - 1), computed with West's
+// algorithm. It equals Var for unit weights.
+// returns NaN for mismatching sizes, negative weights or a weight sum < 1
+func (a *Vector) WeightedVar(w *Vector) float64 {
+	if !validWeights(a, w) {
+		return math.NaN()
+	}
+	var wsum, mean, s float64 = 0, 0, 0
+	for i := 0; i < a.Size(); i++ {
+		wi := w.at(i)
+		if wi == 0 {
+			continue
+		}
+		wsum += wi
+		delta := a.at(i) - mean
+		mean += (wi / wsum) * delta
+		s += wi * delta * (a.at(i) - mean)
+	}
+	switch {
+	case wsum < 1:
+		return math.NaN()
+	case wsum == 1:
+		return 0
+	}
+	return s / (wsum - 1)
+}
+
+// WeightedStd returns sqrt(WeightedVar(w)).
+func (a *Vector) WeightedStd(w *Vector) float64 {
+	return math.Sqrt(a.WeightedVar(w))
+}
+
+// WeightedQuantile returns the weighted p-quantile, i.e. the smallest
+// element x such that the weights of the elements <= x sum up to at least
+// p * sum w_i (inverted cumulative distribution function).
+// returns NaN for mismatching sizes, negative weights, a zero weight sum,
+// if an element is NaN or p is not in [0,1]
+func (a *Vector) WeightedQuantile(p float64, w *Vector) float64 {
+	if !validWeights(a, w) || !(p >= 0 && p <= 1) {
+		return math.NaN()
+	}
+	n := a.Size()
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+		if math.IsNaN(a.at(i)) {
+			return math.NaN()
+		}
+	}
+	sort.Slice(idx, func(k, l int) bool { return a.at(idx[k]) < a.at(idx[l]) })
+	var total kahan
+	for i := 0; i < n; i++ {
+		total.add(w.at(i))
+	}
+	target := p * total.value()
+	var cum kahan
+	for _, i := range idx {
+		if w.at(i) == 0 {
+			continue
+		}
+		cum.add(w.at(i))
+		if cum.value() >= target {
+			return a.at(i)
+		}
+	}
+	return a.at(idx[n-1])
+}
+
+// WeightedMedian returns the weighted 0.5-quantile.
+func (a *Vector) WeightedMedian(w *Vector) float64 {
+	return a.WeightedQuantile(0.5, w)
+}
+
+// moments returns the accumulated moments of the elements
+func (a *Vector) moments() (m moments) {
+	for i := 0; i < a.Size(); i++ {
+		m.add(a.at(i))
+	}
+	return
+}
+
+// sorted returns the elements in ascending order, NaNs first
+func (a *Vector) sorted() (s []float64) {
+	s = a.Slice()
+	sort.Float64s(s)
+	return
+}
+
+// quantileSorted returns the p-quantile of the sorted slice s
+func quantileSorted(s []float64, p float64, method QuantileMethod) float64 {
+	n := len(s)
+	if n == 0 || !(p >= 0 && p <= 1) || math.IsNaN(s[0]) {
+		return math.NaN()
+	}
+	h := float64(n-1) * p
+	lo := int(math.Floor(h))
+	hi := int(math.Ceil(h))
+	// no interpolation between equal elements, which could be infinite
+	if s[lo] == s[hi] {
+		return s[lo]
+	}
+	switch method {
+	case QuantileLower:
+		return s[lo]
+	case QuantileHigher:
+		return s[hi]
+	case QuantileNearest:
+		return s[int(math.RoundToEven(h))]
+	case QuantileMidpoint:
+		return (s[lo] + s[hi]) / 2
+	case QuantileLinear:
+		return s[lo] + (h-float64(lo))*(s[hi]-s[lo])
+	}
+	return math.NaN()
+}
+
+// validWeights reports whether w is a valid weight vector for a
+func validWeights(a, w *Vector) bool {
+	if a.Size() != w.Size() || a.Size() == 0 {
+		return false
+	}
+	var wsum float64 = 0
+	for i := 0; i < w.Size(); i++ {
+		if !(w.at(i) >= 0) {
+			return false
+		}
+		wsum += w.at(i)
+	}
+	return wsum > 0
+}
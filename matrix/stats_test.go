@@ -0,0 +1,170 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMomentsStable(t *testing.T) {
+	rng := rand.New(rand.NewSource(22))
+	n := 1000
+	v := ZeroVec(n)
+	shifted := ZeroVec(n)
+	for i := 0; i < n; i++ {
+		x := rng.ExpFloat64()
+		shifted.Set(i, x)
+		v.Set(i, 1e9+x)
+	}
+	// two pass reference on the unshifted data
+	var mean float64
+	for _, x := range shifted.Slice() {
+		mean += x
+	}
+	mean /= float64(n)
+	var m2, m3, m4 float64
+	for _, x := range shifted.Slice() {
+		d := x - mean
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+	fn := float64(n)
+	tests := []struct {
+		name      string
+		got, want float64
+		tol       float64
+	}{
+		{"Mean", v.Mean() - 1e9, mean, 1e-6},
+		{"Var", v.Var(), m2 / (fn - 1), 1e-6},
+		{"Skewness", v.Skewness(), (m3 / fn) / math.Pow(m2/fn, 1.5), 1e-4},
+		{"Kurtosis", v.Kurtosis(), (m4/fn)/math.Pow(m2/fn, 2) - 3, 1e-3},
+		{"Sum", VecFromSlice([]float64{1, 1e100, 1, -1e100}).Sum(), 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if math.Abs(tt.got-tt.want) > tt.tol {
+				t.Fatal(tt.got, tt.want)
+			}
+		})
+	}
+	// merging moments of two parts
+	var a, b, c moments
+	for i, x := range shifted.Slice() {
+		c.add(x)
+		if i < 300 {
+			a.add(x)
+		} else {
+			b.add(x)
+		}
+	}
+	a.merge(&b)
+	if math.Abs(a.m2-c.m2) > 1e-8*c.m2 || math.Abs(a.m3-c.m3) > 1e-8*math.Abs(c.m3) || math.Abs(a.m4-c.m4) > 1e-8*c.m4 {
+		t.Fatal("merge", a, c)
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	inf := math.Inf(1)
+	w := []float64{1, 2, 3, 4}
+	tests := []struct {
+		x    []float64
+		p    float64
+		m    QuantileMethod
+		want float64
+	}{
+		{w, 0.25, QuantileLinear, 1.75},
+		{w, 0.25, QuantileLower, 1},
+		{w, 0.25, QuantileHigher, 2},
+		{w, 0.25, QuantileNearest, 2},
+		{w, 0.25, QuantileMidpoint, 1.5},
+		{w, 0.5, QuantileNearest, 3},
+		{w, 0, QuantileLinear, 1},
+		{w, 1, QuantileLinear, 4},
+		{w, 1.5, QuantileLinear, math.NaN()},
+		{[]float64{1, math.NaN()}, 0.5, QuantileLinear, math.NaN()},
+		{[]float64{1, inf}, 1, QuantileLinear, inf},
+		{[]float64{1, inf}, 1, QuantileMidpoint, inf},
+		{[]float64{-inf, 1}, 0, QuantileLinear, -inf},
+		{[]float64{-inf, 1}, 0, QuantileMidpoint, -inf},
+		{[]float64{inf, inf}, 0.5, QuantileLinear, inf},
+		{[]float64{1e308, 1e308, 1e308}, 0.5, QuantileMidpoint, 1e308},
+		{[]float64{1, 2, 3}, 0.25, QuantileMidpoint, 1.5},
+	}
+	for _, tt := range tests {
+		if got := VecFromSlice(tt.x).Quantile(tt.p, tt.m); !floatClose(got, tt.want, 0) {
+			t.Errorf("%v %v %v: %v, want %v", tt.x, tt.p, tt.m, got, tt.want)
+		}
+	}
+	v := VecFromSlice(w)
+	if v.Median() != 2.5 {
+		t.Fatal(v.Median())
+	}
+	vecClose(t, "Quantiles", v.Quantiles([]float64{0, 0.5, 1}, QuantileLinear), VecFromSlice([]float64{1, 2.5, 4}), 0)
+	md, cnt := VecFromSlice([]float64{3, 1, 3, 1, 2, math.NaN(), math.NaN(), math.NaN()}).Mode()
+	if md != 1 || cnt != 2 {
+		t.Fatal("Mode", md, cnt)
+	}
+}
+
+func TestWeightedStats(t *testing.T) {
+	w := VecFromSlice([]float64{1, 2, 3, 4})
+	ones := VecFromSlice([]float64{1, 1, 1, 1})
+	// frequency weights are the same as repeated elements
+	freq := VecFromSlice([]float64{2, 0, 1, 1})
+	rep := VecFromSlice([]float64{1, 1, 3, 4})
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"WeightedMean ones", w.WeightedMean(ones), w.Mean()},
+		{"WeightedVar ones", w.WeightedVar(ones), w.Var()},
+		{"WeightedMean", w.WeightedMean(freq), rep.Mean()},
+		{"WeightedVar", w.WeightedVar(freq), rep.Var()},
+		{"WeightedMedian", w.WeightedMedian(freq), 1},
+		{"WeightedQuantile", w.WeightedQuantile(0.75, freq), 3},
+		{"WeightedQuantile 1", w.WeightedQuantile(1, freq), 4},
+		{"negative weight", w.WeightedMean(VecFromSlice([]float64{1, -1, 1, 1})), math.NaN()},
+		{"size mismatch", w.WeightedMean(ZeroVec(1)), math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !floatClose(tt.got, tt.want, 1e-14) {
+				t.Fatal(tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsEmpty(t *testing.T) {
+	var e Vector
+	for name, x := range map[string]float64{
+		"Mean": e.Mean(), "Var": e.Var(), "Median": e.Median(), "Skewness": e.Skewness(),
+		"constant Skewness": VecFromSlice([]float64{5, 5}).Skewness(),
+	} {
+		if !math.IsNaN(x) {
+			t.Error(name, x)
+		}
+	}
+	if VecFromSlice([]float64{5}).Var() != 0 {
+		t.Fatal("Var of a single element")
+	}
+	for name, f := range map[string]func() (float64, error){
+		"StdSafe": e.StdSafe, "SkewnessSafe": e.SkewnessSafe, "KurtosisSafe": e.KurtosisSafe,
+	} {
+		if x, err := f(); !math.IsNaN(x) || !errors.Is(err, ErrInvalidArgument) {
+			t.Error(name, x, err)
+		}
+	}
+	if x, err := VecFromSlice([]float64{2, 2, 2}).SkewnessSafe(); !math.IsNaN(x) || err != nil {
+		t.Fatal(x, err)
+	}
+	w := VecFromSlice([]float64{1, 2, 3, 10})
+	if x, err := w.StdSafe(); x != w.Std() || err != nil {
+		t.Fatal(x, err)
+	}
+	if x, err := w.KurtosisSafe(); x != w.Kurtosis() || err != nil {
+		t.Fatal(x, err)
+	}
+}
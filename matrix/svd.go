@@ -0,0 +1,301 @@
+/*	This file implements the singular value decomposition with the
+	one-sided Jacobi method and the tools built on top of it
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// NewSVD computes the singular value decomposition a = U * diag(s) * V^T
+// of the rows x cols matrix a with k = min(rows, cols) singular values.
+// If full is set, U is rows x rows and V is cols x cols,
+// otherwise (thin SVD) U is rows x k and V is cols x k.
+// Returns an error, if the iteration doesn't converge.
+func NewSVD(a Mat, full bool) (f *SVD, e error) {
+	f, e = newSVD(asMatrix(a), full)
+	return
+}
+
+// newSVD implements NewSVD for a dense matrix
+func newSVD(a *Matrix, full bool) (f *SVD, e error) {
+	// work on the orientation with at least as many rows as columns
+	var w *Matrix
+	transposed := a.rows < a.cols
+	if transposed {
+		w = transpose(a)
+	} else {
+		w = a.CopyMat()
+	}
+	m := w.rows
+	n := w.cols
+	v, _ := IdMat(n, n)
+	if !jacobiSVD(w.entries, v.entries, m, n) {
+		e = ErrNoConvergence
+		return
+	}
+	// singular values are the column norms of w
+	s := make([]float64, n)
+	for j := 0; j < n; j++ {
+		for i := 0; i < m; i++ {
+			s[j] = math.Hypot(s[j], w.entries[i*n+j])
+		}
+	}
+	// sort descending
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return s[order[i]] > s[order[j]]
+	})
+	// normalize left singular vectors, columns of zero singular values
+	// are completed to an orthonormal basis afterwards
+	uCols := n
+	if full {
+		uCols = m
+	}
+	u, _ := ZeroMat(m, uCols)
+	vs, _ := ZeroMat(n, n)
+	values := ZeroVec(n)
+	tol := float64(m) * eps * s[order[0]]
+	valid := make([]bool, uCols)
+	for k, idx := range order {
+		values.entries[k] = s[idx]
+		for i := 0; i < n; i++ {
+			vs.entries[i*n+k] = v.entries[i*n+idx]
+		}
+		if s[idx] <= tol || s[idx] == 0 {
+			continue
+		}
+		for i := 0; i < m; i++ {
+			u.entries[i*uCols+k] = w.entries[i*n+idx] / s[idx]
+		}
+		valid[k] = true
+	}
+	completeBasis(u, valid)
+	// undo transposition
+	f = new(SVD)
+	f.values = values
+	if transposed {
+		f.u = vs
+		f.v = u
+	} else {
+		f.u = u
+		f.v = vs
+	}
+	return
+}
+
+// U returns the left singular vectors as columns of a matrix.
+func (f *SVD) U() (u *Matrix) {
+	u = f.u.CopyMat()
+	return
+}
+
+// V returns the right singular vectors as columns of a matrix.
+func (f *SVD) V() (v *Matrix) {
+	v = f.v.CopyMat()
+	return
+}
+
+// Values returns the singular values in descending order.
+func (f *SVD) Values() (s *Vector) {
+	s = f.values.CopyVec()
+	return
+}
+
+// NumericalRank returns the number of singular values larger than tol.
+// If tol <= 0, the default tolerance max(rows, cols) * eps * s[0] is used.
+func (f *SVD) NumericalRank(tol float64) (rank int) {
+	if tol <= 0 {
+		tol = f.defaultTol()
+	}
+	for _, s := range f.values.entries {
+		if s > tol {
+			rank++
+		}
+	}
+	return
+}
+
+// Cond2 returns the condition number s[0] / s[k-1] in the 2-norm.
+// Returns +Inf for rank deficient matrices.
+func (f *SVD) Cond2() float64 {
+	k := f.values.Size()
+	if f.values.entries[k-1] == 0 {
+		return math.Inf(1)
+	}
+	return f.values.entries[0] / f.values.entries[k-1]
+}
+
+// PseudoInverse returns the Moore-Penrose pseudo-inverse V * diag(1/s) * U^T,
+// where singular values below the default tolerance of NumericalRank are
+// treated as zero.
+func (f *SVD) PseudoInverse() (p *Matrix) {
+	rank := f.NumericalRank(0)
+	m := f.u.rows
+	n := f.v.rows
+	p, _ = ZeroMat(n, m)
+	for k := 0; k < rank; k++ {
+		inv := 1 / f.values.entries[k]
+		for i := 0; i < n; i++ {
+			vik := f.v.getEntry(i, k) * inv
+			if vik == 0 {
+				continue
+			}
+			for j := 0; j < m; j++ {
+				p.entries[i*m+j] += vik * f.u.getEntry(j, k)
+			}
+		}
+	}
+	return
+}
+
+// Truncate returns the best rank k approximation U_k * diag(s_k) * V_k^T
+// of the decomposed matrix.
+// Returns an error, if k is not in [1, min(rows, cols)].
+func (f *SVD) Truncate(k int) (a *Matrix, e error) {
+	// check k
+	if k < 1 || k > f.values.Size() {
+		e = fmt.Errorf("%w: rank %d not in [1, %d]", ErrInvalidArgument, k, f.values.Size())
+		return
+	}
+	m := f.u.rows
+	n := f.v.rows
+	a, _ = ZeroMat(m, n)
+	for l := 0; l < k; l++ {
+		s := f.values.entries[l]
+		for i := 0; i < m; i++ {
+			uil := f.u.getEntry(i, l) * s
+			if uil == 0 {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				a.entries[i*n+j] += uil * f.v.getEntry(j, l)
+			}
+		}
+	}
+	return
+}
+
+// defaultTol returns the tolerance below which singular values are zero.
+func (f *SVD) defaultTol() float64 {
+	return float64(max(f.u.rows, f.v.rows)) * eps * f.values.entries[0]
+}
+
+// transpose returns a new matrix with the transposed contents of a.
+func transpose(a *Matrix) (t *Matrix) {
+	t, _ = ZeroMat(a.cols, a.rows)
+	for i := 0; i < a.rows; i++ {
+		for j := 0; j < a.cols; j++ {
+			t.entries[j*a.rows+i] = a.getEntry(i, j)
+		}
+	}
+	return
+}
+
+// completeBasis replaces the columns j of u with valid[j] == false,
+// such that the columns of u form an orthonormal set.
+// The valid columns have to be orthonormal already.
+func completeBasis(u *Matrix, valid []bool) {
+	m := u.rows
+	c := u.cols
+	candidate := 0
+	x := make([]float64, m)
+	for j := 0; j < c; j++ {
+		if valid[j] {
+			continue
+		}
+		// try unit vectors until one is independent of the valid columns
+		for ; candidate < m; candidate++ {
+			for i := range x {
+				x[i] = 0
+			}
+			x[candidate] = 1
+			// orthogonalize twice for numerical stability
+			for pass := 0; pass < 2; pass++ {
+				for k := 0; k < c; k++ {
+					if !valid[k] {
+						continue
+					}
+					var dot float64 = 0
+					for i := 0; i < m; i++ {
+						dot += u.entries[i*c+k] * x[i]
+					}
+					for i := 0; i < m; i++ {
+						x[i] -= dot * u.entries[i*c+k]
+					}
+				}
+			}
+			var nrm float64 = 0
+			for i := range x {
+				nrm = math.Hypot(nrm, x[i])
+			}
+			if nrm > 0.5 {
+				for i := 0; i < m; i++ {
+					u.entries[i*c+j] = x[i] / nrm
+				}
+				valid[j] = true
+				candidate++
+				break
+			}
+		}
+	}
+}
+
+// jacobiSVD orthogonalizes the columns of the m x n matrix w (m >= n)
+// by plane rotations, which are accumulated in the n x n matrix v.
+// Returns false, if the method didn't converge.
+func jacobiSVD(w, v []float64, m, n int) bool {
+	for sweep := 0; sweep < maxJacobiSweeps; sweep++ {
+		rotated := false
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				// compute entries of the 2x2 gram matrix
+				var alpha, beta, gamma float64 = 0, 0, 0
+				for i := 0; i < m; i++ {
+					wp := w[i*n+p]
+					wq := w[i*n+q]
+					alpha += wp * wp
+					beta += wq * wq
+					gamma += wp * wq
+				}
+				if gamma == 0 || math.Abs(gamma) <= eps*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+				// compute rotation, which makes columns p and q orthogonal
+				zeta := (beta - alpha) / (2 * gamma)
+				var t float64
+				if zeta >= 0 {
+					t = 1 / (zeta + math.Sqrt(1+zeta*zeta))
+				} else {
+					t = -1 / (-zeta + math.Sqrt(1+zeta*zeta))
+				}
+				c := 1 / math.Sqrt(1+t*t)
+				s := t * c
+				for i := 0; i < m; i++ {
+					wp := w[i*n+p]
+					wq := w[i*n+q]
+					w[i*n+p] = c*wp - s*wq
+					w[i*n+q] = s*wp + c*wq
+				}
+				for i := 0; i < n; i++ {
+					vp := v[i*n+p]
+					vq := v[i*n+q]
+					v[i*n+p] = c*vp - s*vq
+					v[i*n+q] = s*vp + c*vq
+				}
+			}
+		}
+		if !rotated {
+			return true
+		}
+	}
+	return false
+}
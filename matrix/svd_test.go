@@ -0,0 +1,83 @@
+package matrix
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSVD(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for _, dims := range [][2]int{{5, 3}, {3, 5}, {4, 4}, {1, 3}, {6, 1}} {
+		m, n := dims[0], dims[1]
+		k := m
+		if n < k {
+			k = n
+		}
+		a := randMat(m, n, rng)
+		for _, full := range []bool{false, true} {
+			f, e := NewSVD(a, full)
+			if e != nil {
+				t.Fatal(dims, e)
+			}
+			u, s, v := f.U(), f.Values(), f.V()
+			if s.Size() != k {
+				t.Fatal("number of singular values", s.Size())
+			}
+			for i := 1; i < k; i++ {
+				if s.Get(i) > s.Get(i-1) {
+					t.Fatal("singular values not descending")
+				}
+			}
+			idu, _ := IdMat(u.Cols(), u.Cols())
+			matClose(t, "U^T U", u.TMul(u), idu, 1e-12)
+			idv, _ := IdMat(v.Cols(), v.Cols())
+			matClose(t, "V^T V", v.TMul(v), idv, 1e-12)
+			sm, _ := ZeroMat(u.Cols(), v.Cols())
+			for i := 0; i < k; i++ {
+				sm.Set(i, i, s.Get(i))
+			}
+			matClose(t, "U S V^T", u.Mul(sm).MulT(v), a, 1e-12)
+			matClose(t, "A A^+ A", a.Mul(f.PseudoInverse()).Mul(a), a, 1e-10)
+			tr, _ := f.Truncate(k)
+			matClose(t, "Truncate", tr, a, 1e-12)
+		}
+	}
+}
+
+func TestSVDRankDeficient(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	// 6x4 matrix of rank 2
+	b := randMat(6, 2, rng).MulT(randMat(4, 2, rng))
+	f, e := NewSVD(b, true)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if f.NumericalRank(0) != 2 {
+		t.Fatal(f.NumericalRank(0))
+	}
+	if c := f.Cond2(); !math.IsInf(c, 1) && c < 1e12 {
+		t.Fatal(c)
+	}
+	id, _ := IdMat(6, 6)
+	matClose(t, "full U", f.U().TMul(f.U()), id, 1e-12)
+	matClose(t, "A A^+ A", b.Mul(f.PseudoInverse()).Mul(b), b, 1e-10)
+	tr, _ := f.Truncate(2)
+	matClose(t, "Truncate", tr, b, 1e-10)
+	z, _ := ZeroMat(3, 2)
+	fz, e := NewSVD(z, true)
+	if e != nil || fz.NumericalRank(0) != 0 {
+		t.Fatal(e)
+	}
+}
+
+func TestSVDCond(t *testing.T) {
+	d, _ := MatrixFromSlice([][]float64{{3, 0}, {0, 1}})
+	f, _ := NewSVD(d, false)
+	if math.Abs(f.Cond2()-3) > 1e-14 {
+		t.Fatal(f.Cond2())
+	}
+	if _, e := f.Truncate(3); e == nil {
+		t.Fatal("expected error for k > rank")
+	}
+}
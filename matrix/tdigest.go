@@ -0,0 +1,108 @@
+/*	This file implements the merging t-digest of Dunning and Ertl for
+	approximate quantiles of streams (https://arxiv.org/abs/1902.04023)
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+import (
+	"math"
+	"sort"
+)
+
+// default compression of a t-digest, about the number of centroids
+const defaultCompression = 100
+
+// add adds the sample x with weight w
+func (t *tdigest) add(x, w float64) {
+	t.buffer = append(t.buffer, centroid{mean: x, weight: w})
+	if float64(len(t.buffer)) >= 5*t.delta() {
+		t.compress()
+	}
+}
+
+// merge adds the centroids of o
+func (t *tdigest) merge(o *tdigest) {
+	t.buffer = append(t.buffer, o.centroids...)
+	t.buffer = append(t.buffer, o.buffer...)
+	t.compress()
+}
+
+// compress merges the buffer into the centroids, such that the size of
+// every centroid is bounded by the scale function k(q) = delta/(2 pi) asin(2q-1)
+func (t *tdigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	all := append(t.centroids, t.buffer...)
+	t.buffer = t.buffer[:0]
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+	var total float64 = 0
+	for _, c := range all {
+		total += c.weight
+	}
+	merged := make([]centroid, 0, int(t.delta())+1)
+	cur := all[0]
+	var before float64 = 0
+	for _, c := range all[1:] {
+		// merge c into cur, if the merged centroid spans at most one unit of k
+		if t.scale((before+cur.weight+c.weight)/total)-t.scale(before/total) <= 1 {
+			cur.weight += c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / cur.weight
+			continue
+		}
+		merged = append(merged, cur)
+		before += cur.weight
+		cur = c
+	}
+	t.centroids = append(merged, cur)
+}
+
+// quantile returns the approximate p-quantile, min and max are the
+// exact extrema of the samples
+func (t *tdigest) quantile(p, min, max float64) float64 {
+	t.compress()
+	cs := t.centroids
+	if len(cs) == 0 || !(p >= 0 && p <= 1) {
+		return math.NaN()
+	}
+	if len(cs) == 1 {
+		return cs[0].mean
+	}
+	var total float64 = 0
+	for _, c := range cs {
+		total += c.weight
+	}
+	target := p * total
+	// interpolate between the extrema and the first and last centroid
+	first, last := cs[0], cs[len(cs)-1]
+	if target < first.weight/2 {
+		return min + (first.mean-min)*target/(first.weight/2)
+	}
+	if target > total-last.weight/2 {
+		return max - (max-last.mean)*(total-target)/(last.weight/2)
+	}
+	// interpolate between the centers of neighbouring centroids
+	center := first.weight / 2
+	for i := 1; i < len(cs); i++ {
+		next := center + (cs[i-1].weight+cs[i].weight)/2
+		if target <= next {
+			return cs[i-1].mean + (cs[i].mean-cs[i-1].mean)*(target-center)/(next-center)
+		}
+		center = next
+	}
+	return last.mean
+}
+
+// scale is the scale function k_1 of the t-digest
+func (t *tdigest) scale(q float64) float64 {
+	return t.delta() / (2 * math.Pi) * math.Asin(2*math.Min(q, 1)-1)
+}
+
+// delta returns the compression, default if not set
+func (t *tdigest) delta() float64 {
+	if t.compression <= 0 {
+		return defaultCompression
+	}
+	return t.compression
+}
@@ -4,14 +4,281 @@
 
 package matrix
 
-// definiton of vector type
-type Vector struct {
-	entries []float64
+import (
+	"sync"
+)
+
+// definiton of vector type.
+// Vector is the float64 instantiation of GenVector with its own methods,
+// a *Vector converts to *GenVector[float64] and back without copying.
+type Vector GenVector[float64]
+
+// definition of matrix type.
+// Matrix is the float64 instantiation of GenMatrix with its own methods,
+// a *Matrix converts to *GenMatrix[float64] and back without copying.
+type Matrix GenMatrix[float64]
+
+// definition of LU decomposition type.
+// Stores the factors of P * A = L * U with partial pivoting.
+// L (unit lower triangular) and U (upper triangular) share one matrix.
+type LU struct {
+	lu    *Matrix
+	pivot []int
+	sign  float64
+	tol   float64
+}
+
+// definition of QR decomposition type.
+// Stores the Householder vectors of A * P = Q * R below the diagonal
+// and the diagonal of R separately.
+type QR struct {
+	qr    *Matrix
+	rdiag []float64
+	perm  []int
+}
+
+// definition of Cholesky decomposition type.
+// Stores the lower triangular factor L of A = L * L^T.
+type Cholesky struct {
+	l *Matrix
+}
+
+// definition of symmetric eigenvalue decomposition type.
+// Stores A = V * diag(values) * V^T with orthogonal V.
+type EigenSym struct {
+	values  *Vector
+	vectors *Matrix
+}
+
+// definition of general eigenvalue decomposition type.
+// Stores the (possibly complex) eigenvalues re + i*im and
+// optionally the right eigenvectors in real form.
+type Eigen struct {
+	re      *Vector
+	im      *Vector
+	vectors *Matrix
+}
+
+// definition of singular value decomposition type.
+// Stores A = U * diag(values) * V^T with singular values in descending order.
+type SVD struct {
+	u      *Matrix
+	values *Vector
+	v      *Matrix
+}
+
+// definition of principal component analysis type.
+// Stores the column means of the data, the leading principal axes as
+// columns of components and the variances of all principal components.
+type PCA struct {
+	means      *Vector
+	components *Matrix
+	variances  *Vector
+}
+
+// definition of coordinate (triplet) sparse matrix type.
+// The k-th stored entry has the value values[k] at (rowIdx[k], colIdx[k]).
+// Duplicate entries are allowed and summed up.
+type COO struct {
+	rows   int
+	cols   int
+	rowIdx []int
+	colIdx []int
+	values []float64
+}
+
+// definition of compressed sparse storage, shared by CSR and CSC.
+// The minor indices and values of the major index k are stored in
+// indices[indptr[k]:indptr[k+1]] and values[indptr[k]:indptr[k+1]],
+// sorted by minor index and without duplicates.
+type compressed struct {
+	major   int
+	minor   int
+	indptr  []int
+	indices []int
+	values  []float64
+}
+
+// definition of compressed sparse row matrix type.
+// The major index is the row, the minor index the column.
+type CSR struct {
+	compressed
+}
+
+// definition of compressed sparse column matrix type.
+// The major index is the column, the minor index the row.
+type CSC struct {
+	compressed
+}
+
+// definition of linear operator type.
+// An Operator only has to provide the matrix-vector product,
+// Matrix, CSR and CSC are Operators.
+type Operator interface {
+	MulVec(v *Vector) *Vector
+}
+
+// definition of preconditioner type.
+// Apply returns an approximation of M^-1 * r for a preconditioner M.
+type Preconditioner interface {
+	Apply(r *Vector) *Vector
+}
+
+// definition of Jacobi (diagonal) preconditioner type.
+type Jacobi struct {
+	inv *Vector
+}
+
+// definition of incomplete LU preconditioner type without fill-in.
+// The unit lower triangle L and the upper triangle U are stored together,
+// diag holds the position of the diagonal entries in lu.
+type ILU0 struct {
+	lu   *CSR
+	diag []int
 }
 
-// definition of matrix type
-type Matrix struct {
+// definition of settings for the iterative solvers.
+// Zero values select the defaults.
+type IterSettings struct {
+	Tol     float64        // relative residual tolerance ||b - a*x|| <= Tol*||b||, default 1e-10
+	MaxIter int            // maximum number of iterations, default 10*n
+	Restart int            // restart length of GMRES, default min(n, 30)
+	X0      *Vector        // initial guess, default zero
+	Precond Preconditioner // preconditioner, default none
+}
+
+// definition of statistics of the iterative solvers.
+type IterStats struct {
+	Iterations int       // number of iterations done
+	Residual   float64   // final relative residual
+	History    []float64 // relative residual after every iteration, History[0] is the initial one
+	Converged  bool
+}
+
+// definition of the common matrix interface.
+// Matrix, COO, CSR and CSC implement Mat, so do user defined structured
+// matrices. At(i,j) returns the element in row i and column j for
+// 0 <= i < r and 0 <= j < c, where (r, c) = Dims().
+// The factorizations accept a Mat and use the storage of a *Matrix directly.
+type Mat interface {
+	Dims() (r, c int)
+	At(i, j int) float64
+}
+
+// definition of the common interface of mutable matrices.
+// Set(i,j,v) sets the element in row i and column j to v.
+type MutableMat interface {
+	Mat
+	Set(i, j int, v float64)
+}
+
+// definition of the real element types of GenMatrix and GenVector.
+type Real interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// definition of the element types of GenMatrix and GenVector.
+type Number interface {
+	Real | ~complex64 | ~complex128
+}
+
+// definition of generic vector type.
+// The i-th element is stored at entries[i*inc], such that a vector
+// can reference a row or column of a matrix without copying.
+type GenVector[T Number] struct {
+	entries []T
+	inc     int
+}
+
+// definition of generic matrix type.
+// The entry (i,j) is stored at entries[i*rstride + j*cstride], such that
+// a matrix can reference a block or the transpose of another matrix
+// without copying. Matrices created by the constructors are row-major
+// with rstride = cols and cstride = 1.
+type GenMatrix[T Number] struct {
 	rows    int
 	cols    int
-	entries []float64
+	rstride int
+	cstride int
+	entries []T
+}
+
+// definition of complex vector type.
+// CVector is GenVector[complex128] with the conjugate and the Hermitian
+// inner product. Its methods return CVectors, (*GenVector[complex128])(v)
+// converts without copying.
+type CVector GenVector[complex128]
+
+// definition of complex matrix type.
+// CMatrix is GenMatrix[complex128] with conjugate transposes, Hermitian
+// products and the complex LU decomposition. Its methods return CMatrices,
+// (*GenMatrix[complex128])(m) converts without copying.
+type CMatrix GenMatrix[complex128]
+
+// definition of complex LU decomposition type.
+// Stores the factors of P * A = L * U with partial pivoting,
+// computed by the same code as LU.
+type CLU struct {
+	lu    *CMatrix
+	pivot []int
+	sign  float64
+	tol   float64
+}
+
+// definition of options for reading and writing delimited text.
+// Zero values select the defaults.
+type CSVOptions struct {
+	Comma  rune     // field delimiter, default ','. Use '\t' for TSV
+	Header bool     // ReadCSV: the first record is a header
+	Names  []string // WriteCSV: column names written as header, if not nil
+}
+
+// definition of options for the LaTeX, Markdown and Compact renderers.
+// Zero values select the defaults.
+type FormatOptions struct {
+	Format  string   // fmt format of the entries, default "%.4g"
+	MaxRows int      // elide rows (…) beyond MaxRows, default no elision
+	MaxCols int      // elide cols (…) beyond MaxCols, default no elision
+	Names   []string // Markdown: column names, default the column indices
+}
+
+// definition of the interpolation methods of quantiles.
+// For the sorted elements x_0 <= ... <= x_(n-1) and h = (n-1)*p the
+// p-quantile is interpolated between x_floor(h) and x_ceil(h).
+type QuantileMethod int
+
+// interpolation methods of quantiles, as in numpy.quantile
+const (
+	QuantileLinear   QuantileMethod = iota // x_floor(h) + (h - floor(h)) * (x_ceil(h) - x_floor(h))
+	QuantileLower                          // x_floor(h)
+	QuantileHigher                         // x_ceil(h)
+	QuantileNearest                        // x_round(h), halves rounded to even
+	QuantileMidpoint                       // (x_floor(h) + x_ceil(h)) / 2
+)
+
+// definition of streaming statistics accumulator type.
+// It keeps the moments, the extrema and a t-digest of the added samples.
+// The zero value is ready to use, all methods are safe for concurrent use.
+type Accumulator struct {
+	mu     sync.Mutex
+	m      moments
+	min    float64
+	max    float64
+	digest tdigest
+}
+
+// definition of the t-digest sketch for approximate quantiles.
+// centroids are merged and sorted by mean, buffer holds unmerged samples.
+type tdigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+}
+
+// definition of a weighted cluster of samples of a t-digest.
+type centroid struct {
+	mean   float64
+	weight float64
 }
@@ -5,40 +5,66 @@
 package matrix
 
 import (
+	"fmt"
 	"math"
 )
 
 // returns the size of vector a.
 func (a *Vector) Size() int {
-	return len(a.entries)
+	if len(a.entries) == 0 {
+		return 0
+	}
+	// entries of a view end with the last element
+	return (len(a.entries)-1)/a.inc + 1
 }
 
 // Get returns the i-th element in vector v.
 // Returns NaN if invalid index
 func (v *Vector) Get(i int) float64 {
-	// check if valid index
-	if i < 0 || i >= v.Size() {
+	elem, e := v.GetSafe(i)
+	if e != nil {
 		return math.NaN()
 	}
-	// return element
-	return v.entries[i]
+	return elem
 }
 
 // Set sets the i-th entry to value.
 // If i is an ivalid index, then nothing is updated
 func (v *Vector) Set(i int, value float64) {
+	v.SetSafe(i, value)
+	return
+}
+
+// GetSafe returns the i-th element in vector v and an error value.
+// Returns an IndexError, if invalid index
+func (v *Vector) GetSafe(i int) (elem float64, e error) {
+	// check if valid index
+	if i < 0 || i >= v.Size() {
+		e = &IndexError{Op: "Get", Index: []int{i}, Dims: v.shape()}
+		return
+	}
+	// return element
+	elem = v.at(i)
+	return
+}
+
+// SetSafe sets the i-th entry to value.
+// Returns an IndexError and doesn't update, if invalid index
+func (v *Vector) SetSafe(i int, value float64) (e error) {
 	// check index
 	if i < 0 || i >= v.Size() {
+		e = &IndexError{Op: "Set", Index: []int{i}, Dims: v.shape()}
 		return
 	}
 	// update i-th entry
-	v.entries[i] = value
+	v.setAt(i, value)
 	return
 }
 
-// CopyVector returns a new vector with the same content
+// CopyVector returns a new vector with the same content.
+// The copy of a view doesn't share the storage anymore.
 func (a *Vector) CopyVec() (v *Vector) {
-	v = VecFromSlice(a.entries)
+	v = VecFromSlice(a.Slice())
 	return
 }
 
@@ -54,7 +80,7 @@ func (a *Vector) Mat() (m *Matrix) {
 func (a *Vector) Slice() (s []float64) {
 	s = make([]float64, a.Size())
 	for i := range s {
-		s[i] = a.entries[i]
+		s[i] = a.at(i)
 	}
 	return
 }
@@ -66,12 +92,12 @@ func (a *Vector) Merge(b *Vector) (c *Vector) {
 	offset := a.Size()
 	c = ZeroVec(length)
 	// copy values from a
-	for i := range a.entries {
-		c.entries[i] = a.entries[i]
+	for i := 0; i < a.Size(); i++ {
+		c.entries[i] = a.at(i)
 	}
 	// copy values from b
-	for i := range b.entries {
-		c.entries[offset+i] = b.entries[i]
+	for i := 0; i < b.Size(); i++ {
+		c.entries[offset+i] = b.at(i)
 	}
 	return
 }
@@ -80,15 +106,24 @@ func (a *Vector) Merge(b *Vector) (c *Vector) {
 // to index e (inclusive). (returns entries: a[s], ... , a[e]).
 // Returns nil if invalid indices.
 func (a *Vector) GetSubVec(s, e int) (v *Vector) {
+	v, _ = a.GetSubVecSafe(s, e)
+	return
+}
+
+// GetSubVecSafe returns the subvector from index s (inclusive)
+// to index e (inclusive), like GetSubVec.
+// Returns an IndexError, if invalid indices.
+func (a *Vector) GetSubVecSafe(s, e int) (v *Vector, err error) {
 	// check indices
 	if s < 0 || s >= a.Size() || e < 0 || e >= a.Size() || s > e {
+		err = &IndexError{Op: "GetSubVec", Index: []int{s, e}, Dims: a.shape()}
 		return
 	}
 	// copy values in new vector
 	length := e - s + 1
 	v = ZeroVec(length)
 	for i := range v.entries {
-		v.entries[i] = a.entries[s+i]
+		v.entries[i] = a.at(s + i)
 	}
 	return
 }
@@ -97,18 +132,28 @@ func (a *Vector) GetSubVec(s, e int) (v *Vector) {
 // in a to the values of b. (modifies entries: a[s], ... , a[e])
 // No update if invalid indices or missmatching sizes.
 func (a *Vector) SetSubVec(s int, e int, b *Vector) {
+	a.SetSubVecSafe(s, e, b)
+	return
+}
+
+// SetSubVecSafe sets subvector from s (inclusive) to e (inclusive), like SetSubVec.
+// Returns an IndexError or a DimensionError and doesn't update,
+// if invalid indices or missmatching sizes.
+func (a *Vector) SetSubVecSafe(s int, e int, b *Vector) (err error) {
 	// check indices
 	if s < 0 || s >= a.Size() || e < 0 || e >= a.Size() || s > e {
+		err = &IndexError{Op: "SetSubVec", Index: []int{s, e}, Dims: a.shape()}
 		return
 	}
 	length := e - s + 1
 	// check sizes
 	if a.Size() < b.Size() || b.Size() != length {
+		err = &DimensionError{Op: "SetSubVec", A: []int{length}, B: b.shape()}
 		return
 	}
 	// set values in a to b
-	for i := range b.entries {
-		a.entries[s+i] = b.entries[i]
+	for i := 0; i < b.Size(); i++ {
+		a.setAt(s+i, b.at(i))
 	}
 	return
 }
@@ -116,14 +161,22 @@ func (a *Vector) SetSubVec(s int, e int, b *Vector) {
 // Add returns c = a + b.
 // Return nil, if sizes not match.
 func (a *Vector) Add(b *Vector) (c *Vector) {
+	c, _ = a.AddSafe(b)
+	return
+}
+
+// AddSafe returns c = a + b.
+// Returns a DimensionError, if sizes not match.
+func (a *Vector) AddSafe(b *Vector) (c *Vector, e error) {
 	// check sizes
 	if a.Size() != b.Size() {
+		e = &DimensionError{Op: "Add", A: a.shape(), B: b.shape()}
 		return
 	}
 	// add vectors
 	c = ZeroVec(a.Size())
-	for i := range a.entries {
-		c.entries[i] = a.entries[i] + b.entries[i]
+	for i := range c.entries {
+		c.entries[i] = a.at(i) + b.at(i)
 	}
 	return
 }
@@ -131,14 +184,22 @@ func (a *Vector) Add(b *Vector) (c *Vector) {
 // Sub returns c = a - b.
 // Return nil, if sizes not match.
 func (a *Vector) Sub(b *Vector) (c *Vector) {
+	c, _ = a.SubSafe(b)
+	return
+}
+
+// SubSafe returns c = a - b.
+// Returns a DimensionError, if sizes not match.
+func (a *Vector) SubSafe(b *Vector) (c *Vector, e error) {
 	// check sizes
 	if a.Size() != b.Size() {
+		e = &DimensionError{Op: "Sub", A: a.shape(), B: b.shape()}
 		return
 	}
 	// substract vectors
 	c = ZeroVec(a.Size())
-	for i := range a.entries {
-		c.entries[i] = a.entries[i] - b.entries[i]
+	for i := range c.entries {
+		c.entries[i] = a.at(i) - b.at(i)
 	}
 	return
 }
@@ -146,11 +207,16 @@ func (a *Vector) Sub(b *Vector) (c *Vector) {
 // Scale returns a scaled vector by factor.
 // Returns nil, if factor = inf, or NaN
 func (a *Vector) Scale(factor float64) (v *Vector) {
+	v, _ = a.ScaleSafe(factor)
+	return
+}
+
+// ScaleSafe returns a scaled vector by factor.
+// Returns ErrInvalidArgument, if factor = inf, or NaN
+func (a *Vector) ScaleSafe(factor float64) (v *Vector, e error) {
 	// check if factor is valid
-	if math.IsNaN(factor) {
-		return
-	}
-	if math.IsInf(factor, 1) || math.IsInf(factor, -1) {
+	if math.IsNaN(factor) || math.IsInf(factor, 0) {
+		e = fmt.Errorf("%w: scale factor %v", ErrInvalidArgument, factor)
 		return
 	}
 	// create scaled matrix
@@ -161,14 +227,22 @@ func (a *Vector) Scale(factor float64) (v *Vector) {
 // CWiseProd computes the compnent-wise product of vectors a and b.
 // Returns nil if sizes don't match.
 func (a *Vector) CWiseProd(b *Vector) (c *Vector) {
+	c, _ = a.CWiseProdSafe(b)
+	return
+}
+
+// CWiseProdSafe computes the compnent-wise product of vectors a and b.
+// Returns a DimensionError, if sizes don't match.
+func (a *Vector) CWiseProdSafe(b *Vector) (c *Vector, e error) {
 	// check sizes
 	if a.Size() != b.Size() {
+		e = &DimensionError{Op: "CWiseProd", A: a.shape(), B: b.shape()}
 		return
 	}
 	// create c
 	c = ZeroVec(a.Size())
-	for i := range a.entries {
-		c.entries[i] = a.entries[i] * b.entries[i]
+	for i := range c.entries {
+		c.entries[i] = a.at(i) * b.at(i)
 	}
 	return
 }
@@ -176,46 +250,96 @@ func (a *Vector) CWiseProd(b *Vector) (c *Vector) {
 // Dot returns the dot product of a and b
 // return NaN if sizes don't match
 func (a *Vector) Dot(b *Vector) float64 {
+	result, e := a.DotSafe(b)
+	if e != nil {
+		return math.NaN()
+	}
+	return result
+}
+
+// DotSafe returns the dot product of a and b and an error value.
+// Returns a DimensionError, if sizes don't match
+func (a *Vector) DotSafe(b *Vector) (result float64, e error) {
 	// check sizes
 	if a.Size() != b.Size() {
-		return math.NaN()
+		e = &DimensionError{Op: "Dot", A: a.shape(), B: b.shape()}
+		return
 	}
-	var result float64 = 0
-	for i := range a.entries {
-		result += a.entries[i] * b.entries[i]
+	for i := 0; i < a.Size(); i++ {
+		result += a.at(i) * b.at(i)
 	}
-	return result
+	return
 }
 
-// Mean returns the mean of the vector
+// Mean returns the mean of the vector, computed with compensated summation.
+// returns NaN for an empty vector
 func (a *Vector) Mean() float64 {
-	var result float64 = 0
-	for i := range a.entries {
-		result += a.entries[i]
+	if a.Size() == 0 {
+		return math.NaN()
 	}
-	result = result / float64(a.Size())
-	return result
+	return a.Sum() / float64(a.Size())
+}
+
+// MeanSafe returns the mean of the vector and an error value.
+// Returns ErrInvalidArgument and NaN for an empty vector
+func (a *Vector) MeanSafe() (mean float64, e error) {
+	// check size
+	if a.Size() == 0 {
+		mean = math.NaN()
+		e = fmt.Errorf("%w: mean of an empty vector", ErrInvalidArgument)
+		return
+	}
+	mean = a.Mean()
+	return
 }
 
 // Min returns the minimum and it's index
+// Returns -1 and NaN for an empty vector
 func (a *Vector) Min() (idx int, min float64) {
-	min = a.entries[0]
-	for i := range a.entries {
-		if a.entries[i] < min {
+	idx, min, _ = a.MinSafe()
+	return
+}
+
+// MinSafe returns the minimum and it's index and an error value.
+// Returns ErrInvalidArgument, -1 and NaN for an empty vector
+func (a *Vector) MinSafe() (idx int, min float64, e error) {
+	// check size
+	if a.Size() == 0 {
+		idx, min = -1, math.NaN()
+		e = fmt.Errorf("%w: minimum of an empty vector", ErrInvalidArgument)
+		return
+	}
+	min = a.at(0)
+	for i := 0; i < a.Size(); i++ {
+		if a.at(i) < min {
 			idx = i
-			min = a.entries[i]
+			min = a.at(i)
 		}
 	}
 	return
 }
 
 // Max returns the maximum and it's index
+// Returns -1 and NaN for an empty vector
 func (a *Vector) Max() (idx int, max float64) {
-	max = a.entries[0]
-	for i := range a.entries {
-		if a.entries[i] > max {
+	idx, max, _ = a.MaxSafe()
+	return
+}
+
+// MaxSafe returns the maximum and it's index and an error value.
+// Returns ErrInvalidArgument, -1 and NaN for an empty vector
+func (a *Vector) MaxSafe() (idx int, max float64, e error) {
+	// check size
+	if a.Size() == 0 {
+		idx, max = -1, math.NaN()
+		e = fmt.Errorf("%w: maximum of an empty vector", ErrInvalidArgument)
+		return
+	}
+	max = a.at(0)
+	for i := 0; i < a.Size(); i++ {
+		if a.at(i) > max {
 			idx = i
-			max = a.entries[i]
+			max = a.at(i)
 		}
 	}
 	return
@@ -245,30 +369,53 @@ func (a *Vector) MaxValue() float64 {
 	return value
 }
 
-// Var returns the variance of the vector entries
+// Var returns the sample variance of the vector entries (divisor n-1),
+// computed with Welford's algorithm. Returns 0 for a single entry
+// and NaN for an empty vector.
 func (a *Vector) Var() float64 {
-	// special case: size = 1
-	if a.Size() == 1 {
-		return 0
-	}
-	// normal case: size > 1
-	vecMean := a.Mean()
-	vecSize := float64(a.Size())
-	squaredMean := vecMean * vecMean
-	var sumOfSquares float64 = 0
-	for i := range a.entries {
-		sumOfSquares += a.entries[i] * a.entries[i]
+	m := a.moments()
+	return m.variance()
+}
+
+// VarSafe returns the sample variance of the vector entries and an error value.
+// Returns ErrInvalidArgument and NaN for an empty vector
+func (a *Vector) VarSafe() (variance float64, e error) {
+	// check size
+	if a.Size() == 0 {
+		variance = math.NaN()
+		e = fmt.Errorf("%w: variance of an empty vector", ErrInvalidArgument)
+		return
 	}
-	result := (float64(1) / float64(vecSize-1.0)) * (sumOfSquares - vecSize*squaredMean)
-	return result
+	variance = a.Var()
+	return
 }
 
 // ApplyFunc returns a new vector which contains the entries of a after applying func f.
 func (a *Vector) ApplyFunc(f func(float64) float64) (v *Vector) {
 	// create new vector
 	v = ZeroVec(a.Size())
-	for i := range a.entries {
-		v.entries[i] = f(a.entries[i])
+	for i := range v.entries {
+		v.entries[i] = f(a.at(i))
+	}
+	return
+}
+
+// MulMat computes the vector-matrix product w = a^T * m,
+// i.e. a is treated as a row vector.
+// Returns nil, if a.Size() != m.Rows().
+func (a *Vector) MulMat(m *Matrix) (w *Vector) {
+	w, _ = a.MulMatSafe(m)
+	return
+}
+
+// MulMatSafe computes the vector-matrix product w = a^T * m.
+// Returns a DimensionError, if a.Size() != m.Rows().
+func (a *Vector) MulMatSafe(m *Matrix) (w *Vector, e error) {
+	// check sizes
+	if a.Size() != m.rows {
+		e = &DimensionError{Op: "MulMat", A: a.shape(), B: m.shape()}
+		return
 	}
+	w = m.TMulVec(a)
 	return
 }
@@ -0,0 +1,139 @@
+/*	This file implements views, which reference the entries of
+	other matrices and vectors without copying them.
+	Changes of a view are visible in the original and vice versa.
+	Author: Lino Telschow, tlino@student.ethz.ch
+*/
+
+package matrix
+
+// BlockView returns a view of the submatrix of a.
+// The indices are inclusive like in GetBlock.
+// Returns nil if invalid indices.
+func (a *Matrix) BlockView(urow, ucol, lrow, lcol int) (b *Matrix) {
+	b, _ = a.BlockViewSafe(urow, ucol, lrow, lcol)
+	return
+}
+
+// BlockViewSafe returns a view of the submatrix of a.
+// Returns an IndexError, if invalid indices
+func (a *Matrix) BlockViewSafe(urow, ucol, lrow, lcol int) (b *Matrix, e error) {
+	// check range
+	if urow < 0 || urow >= a.rows || lrow < 0 || lrow >= a.rows ||
+		ucol < 0 || ucol >= a.cols || lcol < 0 || lcol >= a.cols ||
+		urow > lrow || ucol > lcol {
+		e = &IndexError{Op: "BlockView", Index: []int{urow, ucol, lrow, lcol}, Dims: a.shape()}
+		return
+	}
+	// reference entries from (urow, ucol) to (lrow, lcol)
+	first := urow*a.rstride + ucol*a.cstride
+	last := lrow*a.rstride + lcol*a.cstride
+	b = new(Matrix)
+	b.rows = lrow - urow + 1
+	b.cols = lcol - ucol + 1
+	b.rstride = a.rstride
+	b.cstride = a.cstride
+	b.entries = a.entries[first : last+1]
+	return
+}
+
+// T returns a view of the transpose of a.
+func (a *Matrix) T() (t *Matrix) {
+	t = new(Matrix)
+	t.rows = a.cols
+	t.cols = a.rows
+	t.rstride = a.cstride
+	t.cstride = a.rstride
+	t.entries = a.entries
+	return
+}
+
+// RowView returns a vector, which references the i-th row of a.
+// Returns nil if invalid index
+func (a *Matrix) RowView(i int) (v *Vector) {
+	v, _ = a.RowViewSafe(i)
+	return
+}
+
+// RowViewSafe returns a vector, which references the i-th row of a.
+// Returns an IndexError, if invalid index
+func (a *Matrix) RowViewSafe(i int) (v *Vector, e error) {
+	// check if valid idx
+	if i < 0 || i >= a.rows {
+		e = &IndexError{Op: "RowView", Index: []int{i}, Dims: a.shape()}
+		return
+	}
+	first := i * a.rstride
+	last := first + (a.cols-1)*a.cstride
+	v = new(Vector)
+	v.entries = a.entries[first : last+1]
+	v.inc = a.cstride
+	return
+}
+
+// ColView returns a vector, which references the j-th column of a.
+// Returns nil if invalid index
+func (a *Matrix) ColView(j int) (v *Vector) {
+	v, _ = a.ColViewSafe(j)
+	return
+}
+
+// ColViewSafe returns a vector, which references the j-th column of a.
+// Returns an IndexError, if invalid index
+func (a *Matrix) ColViewSafe(j int) (v *Vector, e error) {
+	// check if valid idx
+	if j < 0 || j >= a.cols {
+		e = &IndexError{Op: "ColView", Index: []int{j}, Dims: a.shape()}
+		return
+	}
+	first := j * a.cstride
+	last := first + (a.rows-1)*a.rstride
+	v = new(Vector)
+	v.entries = a.entries[first : last+1]
+	v.inc = a.rstride
+	return
+}
+
+// SubVecView returns a view of the subvector from index s (inclusive)
+// to index e (inclusive), like GetSubVec.
+// Returns nil if invalid indices.
+func (a *Vector) SubVecView(s, e int) (v *Vector) {
+	v, _ = a.SubVecViewSafe(s, e)
+	return
+}
+
+// SubVecViewSafe returns a view of the subvector from index s (inclusive)
+// to index e (inclusive).
+// Returns an IndexError, if invalid indices.
+func (a *Vector) SubVecViewSafe(s, e int) (v *Vector, err error) {
+	// check indices
+	if s < 0 || s >= a.Size() || e < 0 || e >= a.Size() || s > e {
+		err = &IndexError{Op: "SubVecView", Index: []int{s, e}, Dims: a.shape()}
+		return
+	}
+	v = new(Vector)
+	v.entries = a.entries[s*a.inc : e*a.inc+1]
+	v.inc = a.inc
+	return
+}
+
+// isContiguous reports whether the entries of a are stored row by row
+// without gaps, such that entry (i,j) is at entries[i*cols+j].
+func (a *Matrix) isContiguous() bool {
+	return a.cstride == 1 && (a.rstride == a.cols || a.rows == 1)
+}
+
+// row returns the i-th row of a as slice.
+// a needs cstride = 1.
+func (a *Matrix) row(i int) []float64 {
+	return a.entries[i*a.rstride : i*a.rstride+a.cols]
+}
+
+// at returns the i-th element of a without checking the index.
+func (a *Vector) at(i int) float64 {
+	return a.entries[i*a.inc]
+}
+
+// setAt sets the i-th element of a without checking the index.
+func (a *Vector) setAt(i int, v float64) {
+	a.entries[i*a.inc] = v
+}
@@ -0,0 +1,94 @@
+package matrix
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestViewOps(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	big := randMat(7, 8, rng)
+	blk := big.BlockView(1, 2, 4, 6) // 4x5
+	cp := big.GetBlock(1, 2, 4, 6)
+	tv := blk.T()
+	tc := naiveT(cp)
+	other := randMat(4, 5, rng)
+	tests := []struct {
+		name      string
+		got, want *Matrix
+		tol       float64
+	}{
+		{"BlockView", blk, cp, 0},
+		{"T", tv, tc, 0},
+		{"CopyMat", tv.CopyMat(), tc, 0},
+		{"Add", blk.Add(other), cp.Add(other), 0},
+		{"Sub", other.Sub(blk), other.Sub(cp), 0},
+		{"CWiseProd", blk.CWiseProd(other), cp.CWiseProd(other), 0},
+		{"Scale", tv.Scale(2), tc.Scale(2), 0},
+		{"Mul", blk.Mul(tv), naiveMul(cp, tc), 1e-12},
+		{"MulT", tv.Mul(blk), naiveMul(tc, cp), 1e-12},
+		{"TMul", tv.TMul(tv), naiveMul(cp, tc), 1e-12},
+		{"MulT view", tv.MulT(tv), naiveMul(tc, cp), 1e-12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matClose(t, tt.name, tt.got, tt.want, tt.tol)
+		})
+	}
+	col := big.ColView(3)
+	vecClose(t, "ColView", col, big.GetCol(3), 0)
+	vecClose(t, "RowView of T", tv.RowView(2), cp.GetCol(2), 0)
+	vecClose(t, "MulVec", tv.MulVec(blk.ColView(1)), tc.MulVec(cp.GetCol(1)), 1e-12)
+	vecClose(t, "TMulVec", tv.TMulVec(col.SubVecView(0, 4)), tc.TMulVec(big.GetCol(3).GetSubVec(0, 4)), 1e-12)
+}
+
+func TestViewWriteThrough(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	big := randMat(7, 8, rng)
+	blk := big.BlockView(1, 2, 4, 6)
+	blk.T().Set(0, 0, 42)
+	if big.Get(1, 2) != 42 {
+		t.Fatal("Set on transposed block")
+	}
+	col := big.ColView(3)
+	col.Set(6, -1)
+	if big.Get(6, 3) != -1 {
+		t.Fatal("Set on column")
+	}
+	col.SubVecView(2, 5).SetSubVec(0, 1, VecFromSlice([]float64{7, 8}))
+	if big.Get(2, 3) != 7 || big.Get(3, 3) != 8 {
+		t.Fatal("SetSubVec on sub vector")
+	}
+	blk.SetRow(0, VecFromSlice([]float64{1, 2, 3, 4, 5}))
+	if big.Get(1, 6) != 5 {
+		t.Fatal("SetRow on block")
+	}
+}
+
+func TestViewDecompositions(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	big := randMat(7, 8, rng)
+	sq := big.BlockView(0, 0, 5, 5).T()
+	sqc := sq.CopyMat()
+	if d1, d2 := sq.Det(), sqc.Det(); d1 != d2 {
+		t.Fatal(d1, d2)
+	}
+	b := big.ColView(6).SubVecView(0, 5)
+	x1, _ := sq.Solve(b)
+	x2, _ := sqc.Solve(b.CopyVec())
+	vecClose(t, "Solve", x1, x2, 0)
+	f, _ := NewCholesky(sq.TMul(sq))
+	x3, _ := f.Solve(b)
+	vecClose(t, "Cholesky", sq.TMul(sq).MulVec(x3), b, 1e-8)
+	c := b.CopyVec()
+	if b.Dot(b) != c.Dot(c) || b.Mean() != c.Mean() || b.Var() != c.Var() || b.MaxIdx() != c.MaxIdx() {
+		t.Fatal("vector statistics of a view")
+	}
+}
+
+func TestViewInvalid(t *testing.T) {
+	m, _ := ZeroMat(3, 4)
+	if m.BlockView(2, 0, 1, 0) != nil || m.BlockView(0, 0, 3, 4) != nil || m.ColView(4) != nil || m.RowView(-1) != nil {
+		t.Fatal("invalid views should be nil")
+	}
+}